@@ -0,0 +1,229 @@
+package rwsheets
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+var (
+	ErrInvalidRange = errors.New("invalid A1 range")
+
+	r1c1Pattern = regexp.MustCompile(`^[Rr][0-9]*[Cc][0-9]*$`)
+)
+
+// IndexToColumn: Converts a zero-based column index to its A1 column letters.
+// 0 becomes "A", 25 becomes "Z", 26 becomes "AA" and so on.
+func IndexToColumn(index int64) string {
+	if index < 0 {
+		return ""
+	}
+
+	var letters []byte
+	for index >= 0 {
+		letters = append([]byte{byte('A' + index%26)}, letters...)
+		index = index/26 - 1
+	}
+	return string(letters)
+}
+
+// QuoteSheetName: Returns the sheet name quoted for use in an A1 range, if it needs to be.
+// Names containing anything other than letters, digits and underscores, names starting with a digit,
+// and names that could be mistaken for a cell reference are wrapped in single quotes,
+// with any single quotes in the name doubled.
+func QuoteSheetName(name string) string {
+	if name == "" {
+		return ""
+	}
+
+	needsQuotes := name[0] >= '0' && name[0] <= '9'
+	for _, r := range name {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			needsQuotes = true
+			break
+		}
+	}
+
+	// Names like "A1" or "R1C1" would be read as cell references.
+	if _, _, ok := parseCell(name); ok || r1c1Pattern.MatchString(name) {
+		needsQuotes = true
+	}
+
+	if !needsQuotes {
+		return name
+	}
+	return "'" + strings.ReplaceAll(name, "'", "''") + "'"
+}
+
+// BuildRange: Returns the A1 range for the cells on the sheet, like "'My Sheet'!A1:C10".
+// If a1 is empty the range refers to the whole sheet.
+func BuildRange(sheetName, a1 string) string {
+	sheet := QuoteSheetName(sheetName)
+	switch {
+	case a1 == "":
+		return sheet
+	case sheet == "":
+		return a1
+	}
+	return sheet + "!" + a1
+}
+
+// gridRangeToA1: Converts a GridRange to an A1 range on the sheet with the given title.
+// If the title is empty the range will refer to the first sheet.
+// Unbounded rows or columns are left off the range, so a GridRange without any
+// end indices refers to the whole sheet.
+func gridRangeToA1(title string, gr *sheets.GridRange) string {
+	if gr == nil || (gr.EndRowIndex == 0 && gr.EndColumnIndex == 0) {
+		return QuoteSheetName(title)
+	}
+
+	var start, end string
+	if gr.EndColumnIndex > 0 {
+		start = IndexToColumn(gr.StartColumnIndex)
+		end = IndexToColumn(gr.EndColumnIndex - 1)
+	}
+	if gr.EndRowIndex > 0 {
+		start += fmt.Sprint(gr.StartRowIndex + 1)
+		end += fmt.Sprint(gr.EndRowIndex)
+	}
+
+	return BuildRange(title, start+":"+end)
+}
+
+// CellRef: Returns the A1 reference, like "B2", for the zero-based row and column indices.
+func CellRef(row, col int64) string {
+	return IndexToColumn(col) + fmt.Sprint(row+1)
+}
+
+// RangeFromData: Returns the A1 range, like "Sheet1!B2:F11", covering the rows when written
+// starting at the zero-based row and column. The width is the widest rows.
+// Returns "" if there is no data.
+func RangeFromData(sheetName string, startRow, startCol int64, rows []*sheets.RowData) string {
+	width := MaxColumns(rows)
+	if len(rows) == 0 || width == 0 {
+		return ""
+	}
+
+	end := CellRef(startRow+int64(len(rows))-1, startCol+width-1)
+	return BuildRange(sheetName, CellRef(startRow, startCol)+":"+end)
+}
+
+// splitSheetName: Splits an A1 range into its unquoted sheet name and cell range.
+// Either part will be empty if it isn't included in the range.
+func splitSheetName(a1 string) (string, string) {
+	if strings.HasPrefix(a1, "'") {
+		// Find the closing quote, skipping any escaped quotes.
+		for i := 1; i < len(a1); i++ {
+			if a1[i] != '\'' {
+				continue
+			}
+			if i+1 < len(a1) && a1[i+1] == '\'' {
+				i++
+				continue
+			}
+
+			name := strings.ReplaceAll(a1[1:i], "''", "'")
+			return name, strings.TrimPrefix(a1[i+1:], "!")
+		}
+		return strings.ReplaceAll(a1[1:], "''", "'"), ""
+	}
+
+	if i := strings.LastIndex(a1, "!"); i >= 0 {
+		return a1[:i], a1[i+1:]
+	}
+
+	// Without an exclamation mark this is either a cell range or a sheet name.
+	// A single cell needs both its column and row, since a lone "B" or "2" has to be written as "B:B" or "2:2".
+	if strings.Contains(a1, ":") {
+		if start, end, _ := strings.Cut(a1, ":"); isCell(start) && isCell(end) {
+			return "", a1
+		}
+		return a1, ""
+	}
+	if row, col, ok := parseCell(a1); ok && row >= 0 && col >= 0 {
+		return "", a1
+	}
+	return a1, ""
+}
+
+// isCell: Reports whether the string is an A1 cell, column or row.
+func isCell(cell string) bool {
+	_, _, ok := parseCell(cell)
+	return ok
+}
+
+// maxColumnLetters is the number of letters in the last column Sheets allows, ZZZ.
+const maxColumnLetters = 3
+
+// parseCell: Parses an A1 cell, like "B2", "B" or "2", into its zero-based row and column indices.
+// A missing row or column is returned as -1. Columns past ZZZ aren't valid.
+func parseCell(cell string) (int64, int64, bool) {
+	i := 0
+	col := int64(0)
+	for ; i < len(cell); i++ {
+		c := unicode.ToUpper(rune(cell[i]))
+		if c < 'A' || c > 'Z' {
+			break
+		}
+		col = col*26 + int64(c-'A'+1)
+	}
+	if i > maxColumnLetters {
+		return 0, 0, false
+	}
+
+	row := int64(0)
+	digits := 0
+	for i < len(cell) && cell[i] >= '0' && cell[i] <= '9' {
+		row = row*10 + int64(cell[i]-'0')
+		digits++
+		i++
+	}
+
+	if i != len(cell) || (col == 0 && digits == 0) || (digits > 0 && row == 0) {
+		return 0, 0, false
+	}
+	return row - 1, col - 1, true
+}
+
+// parseA1: Parses an A1 range into its unquoted sheet name and a GridRange without the sheet ID.
+// Unbounded rows or columns are left as 0 end indices on the GridRange.
+func parseA1(a1 string) (string, *sheets.GridRange, error) {
+	name, cells := splitSheetName(a1)
+
+	var gr sheets.GridRange
+	if cells == "" {
+		return name, &gr, nil
+	}
+
+	start, end, isRange := strings.Cut(cells, ":")
+	startRow, startCol, ok := parseCell(start)
+	if !ok {
+		return name, nil, fmt.Errorf("%w: %s", ErrInvalidRange, a1)
+	}
+
+	endRow, endCol := startRow, startCol
+	if isRange {
+		if endRow, endCol, ok = parseCell(end); !ok {
+			return name, nil, fmt.Errorf("%w: %s", ErrInvalidRange, a1)
+		}
+	}
+
+	if startRow >= 0 {
+		gr.StartRowIndex = startRow
+	}
+	if startCol >= 0 {
+		gr.StartColumnIndex = startCol
+	}
+	if endRow >= 0 {
+		gr.EndRowIndex = endRow + 1
+	}
+	if endCol >= 0 {
+		gr.EndColumnIndex = endCol + 1
+	}
+
+	return name, &gr, nil
+}
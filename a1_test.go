@@ -0,0 +1,122 @@
+package rwsheets
+
+import (
+	"errors"
+	"testing"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestParseA1(t *testing.T) {
+	tests := []struct {
+		a1                                 string
+		title                              string
+		startRow, endRow, startCol, endCol int64
+	}{
+		{"Sheet1", "Sheet1", 0, 0, 0, 0},
+		{"Data", "Data", 0, 0, 0, 0},
+		{"Abc", "Abc", 0, 0, 0, 0},
+		{"'My Sheet'!B2:D", "My Sheet", 1, 0, 1, 4},
+		{"'It''s'!A1", "It's", 0, 1, 0, 1},
+		{"Sheet1!A:C", "Sheet1", 0, 0, 0, 3},
+		{"Sheet1!3:5", "Sheet1", 2, 5, 0, 0},
+		{"B2:D10", "", 1, 10, 1, 4},
+		{"C5", "", 4, 5, 2, 3},
+		{"A:A", "", 0, 0, 0, 1},
+		{"ZZZ1", "", 0, 1, 18277, 18278},
+	}
+
+	for _, tt := range tests {
+		title, gr, err := parseA1(tt.a1)
+		if err != nil {
+			t.Errorf("parseA1(%q): %v", tt.a1, err)
+			continue
+		}
+
+		if title != tt.title || gr.StartRowIndex != tt.startRow || gr.EndRowIndex != tt.endRow ||
+			gr.StartColumnIndex != tt.startCol || gr.EndColumnIndex != tt.endCol {
+			t.Errorf("parseA1(%q) = %q rows %d:%d cols %d:%d, want %q rows %d:%d cols %d:%d", tt.a1,
+				title, gr.StartRowIndex, gr.EndRowIndex, gr.StartColumnIndex, gr.EndColumnIndex,
+				tt.title, tt.startRow, tt.endRow, tt.startCol, tt.endCol)
+		}
+	}
+}
+
+func TestParseA1Invalid(t *testing.T) {
+	for _, a1 := range []string{"Sheet1!B2:XYZW3", "Sheet1!A0", "Sheet1!1A"} {
+		if _, _, err := parseA1(a1); !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("parseA1(%q) err = %v, want ErrInvalidRange", a1, err)
+		}
+	}
+}
+
+func TestQuoteSheetName(t *testing.T) {
+	tests := map[string]string{
+		"Sheet1":     "Sheet1",
+		"Data":       "Data",
+		"My Sheet":   "'My Sheet'",
+		"Bob's Data": "'Bob''s Data'",
+		"2024":       "'2024'",
+		"1st":        "'1st'",
+		"A1":         "'A1'",
+		"ZZZ9":       "'ZZZ9'",
+		"R1C1":       "'R1C1'",
+		"rc":         "'rc'",
+		"Q1-Sales":   "'Q1-Sales'",
+		"":           "",
+	}
+
+	for name, want := range tests {
+		if got := QuoteSheetName(name); got != want {
+			t.Errorf("QuoteSheetName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestBuildRange(t *testing.T) {
+	tests := []struct {
+		sheet, a1, want string
+	}{
+		{"My Sheet", "A1:C10", "'My Sheet'!A1:C10"},
+		{"Bob's", "B2", "'Bob''s'!B2"},
+		{"Summary2", "A:A", "Summary2!A:A"},
+		{"My Sheet", "", "'My Sheet'"},
+		{"", "A1:B2", "A1:B2"},
+	}
+
+	for _, tt := range tests {
+		if got := BuildRange(tt.sheet, tt.a1); got != tt.want {
+			t.Errorf("BuildRange(%q, %q) = %q, want %q", tt.sheet, tt.a1, got, tt.want)
+		}
+	}
+
+	// Quoted ranges should parse back to the original name.
+	if name, _, err := parseA1(BuildRange("Bob's Data", "A1")); err != nil || name != "Bob's Data" {
+		t.Errorf("parseA1 = %q, %v", name, err)
+	}
+}
+
+func TestRangeFromData(t *testing.T) {
+	var block []*sheets.RowData
+	for i := 0; i < 10; i++ {
+		block = append(block, textRow("a", "b", "c", "d", "e"))
+	}
+
+	// A 10 row, 5 column block starting at B2.
+	if got := RangeFromData("Sheet1", 1, 1, block); got != "Sheet1!B2:F11" {
+		t.Errorf("got %q, want Sheet1!B2:F11", got)
+	}
+
+	// Ragged rows use the widest row.
+	ragged := []*sheets.RowData{textRow("a"), textRow("a", "b", "c"), nil}
+	if got := RangeFromData("My Sheet", 0, 0, ragged); got != "'My Sheet'!A1:C3" {
+		t.Errorf("got %q, want 'My Sheet'!A1:C3", got)
+	}
+
+	if got := RangeFromData("Sheet1", 0, 0, nil); got != "" {
+		t.Errorf("no rows = %q, want \"\"", got)
+	}
+	if got := RangeFromData("Sheet1", 0, 0, []*sheets.RowData{{}}); got != "" {
+		t.Errorf("empty rows = %q, want \"\"", got)
+	}
+}
@@ -0,0 +1,280 @@
+package rwsheets
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// RateLimiter is used by the Client to wait before each API call.
+// *rate.Limiter from golang.org/x/time/rate satisfies this interface.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// RetryPolicy: struct to be used to set how the Client retries failed API calls.
+type RetryPolicy struct {
+	MaxAttempts int                             // The total number of attempts, including the first. Defaults to 1.
+	Backoff     func(attempt int) time.Duration // Optional. Defaults to exponential backoff starting at 500ms.
+	Retryable   func(err error) bool            // Optional. Defaults to retrying rate limit and server errors.
+}
+
+// DefaultRetryPolicy retries rate limited and server errors up to 5 times.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+}
+
+// backoff: Returns how long to wait before the next attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	if p.Backoff != nil {
+		return p.Backoff(attempt)
+	}
+	return time.Duration(500*(1<<(attempt-1))) * time.Millisecond
+}
+
+// retryable: Reports whether the error should be retried.
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsRetryable(err)
+}
+
+// IsRetryable: Reports whether the error is a rate limit or server error from the API.
+func IsRetryable(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch apiErr.Code {
+	case 429, 500, 502, 503, 504:
+		return true
+	}
+	return false
+}
+
+// Client wraps a sheets service with the cross-cutting settings used by every call,
+// such as the context, retry policy, rate limiter and the fields mask used for writes.
+type Client struct {
+	srv     *sheets.Service
+	ctx     context.Context
+	retry   RetryPolicy
+	limiter RateLimiter
+	fields  string
+	locks   sync.Map // Spreadsheet ID to *sync.Mutex, used by SafeAppend.
+}
+
+// ClientOption is used to override the Client's default settings.
+type ClientOption func(*Client)
+
+// WithContext: Sets the context used for every call the client makes.
+func WithContext(ctx context.Context) ClientOption {
+	return func(c *Client) {
+		c.ctx = ctx
+	}
+}
+
+// WithRetryPolicy: Sets the clients retry policy.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithRateLimiter: Sets the rate limiter the client waits on before every call.
+func WithRateLimiter(limiter RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// WithFields: Sets the fields mask the client uses when writing cells.
+func WithFields(fields string) ClientOption {
+	return func(c *Client) {
+		c.fields = fields
+	}
+}
+
+// NewClient: Returns a new client for the given service.
+// By default the client uses context.Background, no retries, no rate limiting and the "*" fields mask.
+func NewClient(srv *sheets.Service, opts ...ClientOption) *Client {
+	c := &Client{
+		srv:    srv,
+		ctx:    context.Background(),
+		retry:  RetryPolicy{MaxAttempts: 1},
+		fields: "*",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Service: Returns the sheets service used by the client.
+func (c *Client) Service() *sheets.Service {
+	return c.srv
+}
+
+// do: Calls fn, waiting on the rate limiter before each attempt and retrying based on the clients retry policy.
+func (c *Client) do(fn func(ctx context.Context) error) error {
+	return c.doRetry(c.retry, fn)
+}
+
+// doRetry: Calls fn, waiting on the rate limiter before each attempt and retrying based on the given retry policy.
+func (c *Client) doRetry(policy RetryPolicy, fn func(ctx context.Context) error) error {
+	attempts := policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		if c.limiter != nil {
+			if err := c.limiter.Wait(c.ctx); err != nil {
+				return err
+			}
+		}
+
+		err := fn(c.ctx)
+		if err == nil || attempt >= attempts || !policy.retryable(err) {
+			return err
+		}
+
+		select {
+		case <-c.ctx.Done():
+			return c.ctx.Err()
+		case <-time.After(policy.backoff(attempt)):
+		}
+	}
+}
+
+// Get: Retrieve the spreadsheet data for one sheet. See GetSheetData.
+func (c *Client) Get(ssid, readRange string) ([]*sheets.RowData, error) {
+	var rows []*sheets.RowData
+
+	err := c.do(func(ctx context.Context) error {
+		ss, err := c.srv.Spreadsheets.Get(ssid).Ranges(readRange).IncludeGridData(true).Context(ctx).Do()
+		if err != nil {
+			return err
+		}
+
+		grid, err := firstGrid(ss)
+		if err != nil {
+			return err
+		}
+
+		rows = grid.RowData
+		return nil
+	})
+
+	return rows, err
+}
+
+// Update: Update the spreadsheet with new values using the clients fields mask. See UpdateSheetData.
+func (c *Client) Update(ssid string, endColumnIndex, gid, startColumnIndex, startRowIndex int64, newVals []*sheets.RowData) error {
+	request := updateCellsRequest(endColumnIndex, gid, startColumnIndex, startRowIndex, newVals, c.fields)
+	return c.BatchUpdate(ssid, []*sheets.Request{request})
+}
+
+// Append: Appends the rows after the last row with data in the sheet using the clients fields mask.
+func (c *Client) Append(ssid string, gid int64, rows []*sheets.RowData) error {
+	request := sheets.Request{
+		AppendCells: &sheets.AppendCellsRequest{
+			Fields:  c.fields,
+			Rows:    rows,
+			SheetId: gid,
+		},
+	}
+	return c.BatchUpdate(ssid, []*sheets.Request{&request})
+}
+
+// BatchUpdate: Sends the given requests to the spreadsheet in a single batch update.
+//
+// If any of the requests aren't idempotent, like appending cells or inserting rows, only conflict and
+// rate limit errors are retried, no matter the clients retry policy. A server error may be returned
+// after the requests were applied, so retrying it could apply them twice.
+func (c *Client) BatchUpdate(ssid string, requests []*sheets.Request) error {
+	update := sheets.BatchUpdateSpreadsheetRequest{
+		IncludeSpreadsheetInResponse: false,
+		Requests:                     requests,
+	}
+
+	policy := c.retry
+	if !idempotentRequests(requests) {
+		policy.Retryable = isAppendRetryable
+	}
+
+	return c.doRetry(policy, func(ctx context.Context) error {
+		_, err := c.srv.Spreadsheets.BatchUpdate(ssid, &update).Context(ctx).Do()
+		return err
+	})
+}
+
+// SafeAppend: Appends the values after the table found in the range, retrying when the append conflicts
+// with another write or is rate limited. Appends to the same spreadsheet made through this client are run one at a time.
+//
+// Appends aren't idempotent, so only errors that guarantee nothing was written are retried, no matter
+// the clients retry policy. A server error may be returned after the rows were added, so retrying it
+// could add them twice.
+//
+// The lock is process local, so appends from other processes can still race and need to be
+// coordinated outside of this package.
+func (c *Client) SafeAppend(ssid, a1Range string, values [][]interface{}) (*sheets.AppendValuesResponse, error) {
+	lock, _ := c.locks.LoadOrStore(ssid, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	// Conflicts are always retried, using at least the default number of attempts.
+	policy := c.retry
+	policy.MaxAttempts = max(policy.MaxAttempts, DefaultRetryPolicy.MaxAttempts)
+	policy.Retryable = isAppendRetryable
+
+	vr := sheets.ValueRange{
+		Values: values,
+	}
+
+	var resp *sheets.AppendValuesResponse
+	err := c.doRetry(policy, func(ctx context.Context) error {
+		var err error
+		resp, err = c.srv.Spreadsheets.Values.Append(ssid, a1Range, &vr).
+			InsertDataOption("INSERT_ROWS").ValueInputOption("USER_ENTERED").Context(ctx).Do()
+		return err
+	})
+
+	return resp, err
+}
+
+// idempotentRequests: Reports whether applying the requests a second time leaves the spreadsheet unchanged.
+// Requests that add, insert, delete or move by position, or that transform the existing values, aren't.
+func idempotentRequests(requests []*sheets.Request) bool {
+	for _, r := range requests {
+		if r == nil {
+			continue
+		}
+		switch {
+		case r.AppendCells != nil, r.AppendDimension != nil, r.InsertDimension != nil, r.InsertRange != nil,
+			r.DeleteDimension != nil, r.DeleteRange != nil, r.DeleteDimensionGroup != nil, r.DeleteConditionalFormatRule != nil,
+			r.MoveDimension != nil, r.CutPaste != nil, r.AddSheet != nil, r.DuplicateSheet != nil,
+			r.AddBanding != nil, r.AddChart != nil, r.AddConditionalFormatRule != nil, r.AddDataSource != nil,
+			r.AddDimensionGroup != nil, r.AddFilterView != nil, r.DuplicateFilterView != nil, r.AddNamedRange != nil,
+			r.AddProtectedRange != nil, r.AddSlicer != nil, r.CreateDeveloperMetadata != nil,
+			r.FindReplace != nil, r.DeleteDuplicates != nil, r.RandomizeRange != nil, r.TextToColumns != nil:
+			return false
+		}
+	}
+	return true
+}
+
+// isAppendRetryable: Reports whether the error is a conflict or rate limit error, which the API
+// returns before making any changes.
+func isAppendRetryable(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Code == 409 || apiErr.Code == 429
+}
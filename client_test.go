@@ -0,0 +1,219 @@
+package rwsheets
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// noBackoff is a retry backoff that doesn't wait, to keep the tests fast.
+func noBackoff(int) time.Duration { return 0 }
+
+// failFirst: Returns a handler that fails the first n calls with the status code.
+func failFirst(n int, code int) fakeHandler {
+	var calls int32
+	return func(call fakeCall) (int, interface{}) {
+		if int(atomic.AddInt32(&calls, 1)) <= n {
+			return code, apiError(code)
+		}
+		return http.StatusOK, map[string]interface{}{}
+	}
+}
+
+// countingLimiter counts how many times it was waited on.
+type countingLimiter struct {
+	waits int32
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	atomic.AddInt32(&l.waits, 1)
+	return nil
+}
+
+func TestClientRetryPolicy(t *testing.T) {
+	srv, api := newFakeService(t, failFirst(2, http.StatusServiceUnavailable))
+	limiter := &countingLimiter{}
+
+	c := NewClient(srv,
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: noBackoff}),
+		WithRateLimiter(limiter),
+		WithFields("userEnteredValue"),
+	)
+
+	rows := []*sheets.RowData{textRow("a", "b")}
+	if err := c.Update("ssid", 2, 0, 0, 0, rows); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	updates := api.BatchUpdates()
+	if len(updates) != 3 {
+		t.Fatalf("got %d attempts, want 3", len(updates))
+	}
+	if waits := atomic.LoadInt32(&limiter.waits); waits != 3 {
+		t.Errorf("limiter waited %d times, want 3", waits)
+	}
+	if fields := updates[2].Requests[0].UpdateCells.Fields; fields != "userEnteredValue" {
+		t.Errorf("fields = %q, want the clients fields mask", fields)
+	}
+}
+
+func TestClientRetryPolicyGivesUp(t *testing.T) {
+	srv, api := newFakeService(t, failFirst(5, http.StatusServiceUnavailable))
+	c := NewClient(srv, WithRetryPolicy(RetryPolicy{MaxAttempts: 2, Backoff: noBackoff}))
+
+	err := c.BatchUpdate("ssid", []*sheets.Request{{}})
+
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusServiceUnavailable {
+		t.Errorf("err = %v, want the last 503", err)
+	}
+	if calls := len(api.Calls()); calls != 2 {
+		t.Errorf("got %d attempts, want 2", calls)
+	}
+}
+
+func TestClientRetryPolicyCustomRetryable(t *testing.T) {
+	srv, api := newFakeService(t, failFirst(1, http.StatusBadRequest))
+
+	// Bad requests aren't retried by default.
+	c := NewClient(srv, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: noBackoff}))
+	if err := c.BatchUpdate("ssid", []*sheets.Request{{}}); err == nil {
+		t.Fatal("expected the bad request error")
+	}
+	if calls := len(api.Calls()); calls != 1 {
+		t.Errorf("got %d attempts, want 1", calls)
+	}
+
+	srv, api = newFakeService(t, failFirst(1, http.StatusBadRequest))
+	c = NewClient(srv, WithRetryPolicy(RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     noBackoff,
+		Retryable:   func(err error) bool { return true },
+	}))
+	if err := c.BatchUpdate("ssid", []*sheets.Request{{}}); err != nil {
+		t.Fatalf("BatchUpdate: %v", err)
+	}
+	if calls := len(api.Calls()); calls != 2 {
+		t.Errorf("got %d attempts, want 2", calls)
+	}
+}
+
+func TestClientContextCancelled(t *testing.T) {
+	srv, api := newFakeService(t, failFirst(5, http.StatusServiceUnavailable))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := NewClient(srv, WithContext(ctx), WithRetryPolicy(RetryPolicy{
+		MaxAttempts: 5,
+		Backoff: func(int) time.Duration {
+			cancel()
+			return time.Hour
+		},
+	}))
+
+	if err := c.BatchUpdate("ssid", []*sheets.Request{{}}); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if calls := len(api.Calls()); calls != 1 {
+		t.Errorf("got %d attempts, want 1", calls)
+	}
+}
+
+func TestClientSafeAppendRetriesConflicts(t *testing.T) {
+	srv, api := newFakeService(t, func() fakeHandler {
+		var calls int32
+		return func(call fakeCall) (int, interface{}) {
+			switch atomic.AddInt32(&calls, 1) {
+			case 1:
+				return http.StatusConflict, apiError(http.StatusConflict)
+			case 2:
+				return http.StatusTooManyRequests, apiError(http.StatusTooManyRequests)
+			}
+			return http.StatusOK, &sheets.AppendValuesResponse{TableRange: "Log!A1:B10"}
+		}
+	}())
+
+	c := NewClient(srv, WithRetryPolicy(RetryPolicy{Backoff: noBackoff}))
+	resp, err := c.SafeAppend("ssid", "Log!A:B", [][]interface{}{{"a", 1}})
+	if err != nil {
+		t.Fatalf("SafeAppend: %v", err)
+	}
+	if resp.TableRange != "Log!A1:B10" {
+		t.Errorf("table range = %q", resp.TableRange)
+	}
+
+	calls := api.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("got %d attempts, want 3", len(calls))
+	}
+	for _, call := range calls {
+		if !strings.HasSuffix(call.Path, "/values/Log!A:B:append") || call.Query.Get("insertDataOption") != "INSERT_ROWS" {
+			t.Errorf("call = %s %v", call.Path, call.Query)
+		}
+	}
+}
+
+func TestClientSafeAppendDoesNotRetryServerErrors(t *testing.T) {
+	srv, api := newFakeService(t, failFirst(1, http.StatusServiceUnavailable))
+
+	// Even a policy that retries everything shouldn't retry a possibly applied append.
+	c := NewClient(srv, WithRetryPolicy(RetryPolicy{
+		MaxAttempts: 5,
+		Backoff:     noBackoff,
+		Retryable:   func(error) bool { return true },
+	}))
+	if _, err := c.SafeAppend("ssid", "Log!A:B", [][]interface{}{{"a"}}); err == nil {
+		t.Fatal("expected the server error")
+	}
+	if calls := len(api.Calls()); calls != 1 {
+		t.Errorf("got %d attempts, want 1", calls)
+	}
+}
+
+func TestClientAppendDoesNotRetryServerErrors(t *testing.T) {
+	srv, api := newFakeService(t, failFirst(1, http.StatusServiceUnavailable))
+
+	// The default policy retries server errors, but the rows may already have been appended.
+	c := NewClient(srv, WithRetryPolicy(RetryPolicy{MaxAttempts: 5, Backoff: noBackoff}))
+	err := c.Append("ssid", 0, []*sheets.RowData{textRow("a")})
+
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusServiceUnavailable {
+		t.Errorf("err = %v, want the 503", err)
+	}
+	if calls := len(api.Calls()); calls != 1 {
+		t.Errorf("got %d attempts, want 1", calls)
+	}
+
+	// Other non-idempotent requests in a batch aren't retried either, even by a policy that retries everything.
+	srv, api = newFakeService(t, failFirst(1, http.StatusServiceUnavailable))
+	c = NewClient(srv, WithRetryPolicy(RetryPolicy{MaxAttempts: 5, Backoff: noBackoff, Retryable: func(error) bool { return true }}))
+	requests := []*sheets.Request{
+		updateCellsRequest(1, 0, 0, 0, []*sheets.RowData{textRow("a")}, "*"),
+		{InsertDimension: &sheets.InsertDimensionRequest{Range: &sheets.DimensionRange{Dimension: "ROWS", StartIndex: 1, EndIndex: 2}}},
+	}
+	if err := c.BatchUpdate("ssid", requests); err == nil {
+		t.Fatal("expected the server error")
+	}
+	if calls := len(api.Calls()); calls != 1 {
+		t.Errorf("got %d attempts, want 1", calls)
+	}
+}
+
+func TestClientAppendRetriesConflicts(t *testing.T) {
+	srv, api := newFakeService(t, failFirst(2, http.StatusTooManyRequests))
+
+	c := NewClient(srv, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: noBackoff}))
+	if err := c.Append("ssid", 0, []*sheets.RowData{textRow("a")}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if calls := len(api.Calls()); calls != 3 {
+		t.Errorf("got %d attempts, want 3", calls)
+	}
+}
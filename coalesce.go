@@ -0,0 +1,114 @@
+package rwsheets
+
+import (
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// CoalesceRequests: Merges consecutive UpdateCells requests that write to neighbouring ranges into fewer, larger requests.
+//
+// Two requests are merged when they are next to each other in the list, write to the same sheet with the
+// same fields mask, use a Range rather than a Start coordinate, and share a full row or column edge.
+// Rows are padded with empty cells where needed, which has the same effect as the original requests since
+// any cells in a range that the rows don't cover are cleared. All other requests are left in place, and the
+// given requests are never modified.
+func CoalesceRequests(reqs []*sheets.Request) []*sheets.Request {
+	result := reqs
+	for {
+		var merged []*sheets.Request
+		for _, req := range result {
+			if n := len(merged); n > 0 {
+				if m := mergeUpdateCells(merged[n-1], req); m != nil {
+					merged[n-1] = m
+					continue
+				}
+			}
+			merged = append(merged, req)
+		}
+
+		if len(merged) == len(result) {
+			return merged
+		}
+		result = merged
+	}
+}
+
+// mergeUpdateCells: Returns a single request covering both UpdateCells requests, or nil if they can't be merged.
+func mergeUpdateCells(a, b *sheets.Request) *sheets.Request {
+	if a == nil || b == nil || a.UpdateCells == nil || b.UpdateCells == nil {
+		return nil
+	}
+
+	ua, ub := a.UpdateCells, b.UpdateCells
+	ra, rb := ua.Range, ub.Range
+	if ra == nil || rb == nil || ua.Fields != ub.Fields || ra.SheetId != rb.SheetId {
+		return nil
+	}
+	if ra.EndRowIndex == 0 || ra.EndColumnIndex == 0 || rb.EndRowIndex == 0 || rb.EndColumnIndex == 0 {
+		return nil
+	}
+
+	height := int(ra.EndRowIndex - ra.StartRowIndex)
+	width := int(ra.EndColumnIndex - ra.StartColumnIndex)
+
+	merged := *ra
+	var rows []*sheets.RowData
+	switch {
+	case ra.StartRowIndex == rb.StartRowIndex && ra.EndRowIndex == rb.EndRowIndex &&
+		(ra.EndColumnIndex == rb.StartColumnIndex || rb.EndColumnIndex == ra.StartColumnIndex):
+		// Side by side, so join each row.
+		left, right := ua.Rows, ub.Rows
+		leftWidth := width
+		if rb.EndColumnIndex == ra.StartColumnIndex {
+			left, right = right, left
+			leftWidth = int(rb.EndColumnIndex - rb.StartColumnIndex)
+		}
+		left = padCopy(left, height, leftWidth)
+		right = padCopy(right, height, 0)
+		for i := range left {
+			rows = append(rows, &sheets.RowData{
+				Values: append(left[i].Values, right[i].Values...),
+			})
+		}
+		merged.StartColumnIndex = min(ra.StartColumnIndex, rb.StartColumnIndex)
+		merged.EndColumnIndex = max(ra.EndColumnIndex, rb.EndColumnIndex)
+	case ra.StartColumnIndex == rb.StartColumnIndex && ra.EndColumnIndex == rb.EndColumnIndex &&
+		(ra.EndRowIndex == rb.StartRowIndex || rb.EndRowIndex == ra.StartRowIndex):
+		// Stacked, so join the rows.
+		top, bottom := ua.Rows, ub.Rows
+		topHeight := height
+		if rb.EndRowIndex == ra.StartRowIndex {
+			top, bottom = bottom, top
+			topHeight = int(rb.EndRowIndex - rb.StartRowIndex)
+		}
+		rows = append(padCopy(top, topHeight, 0), bottom...)
+		merged.StartRowIndex = min(ra.StartRowIndex, rb.StartRowIndex)
+		merged.EndRowIndex = max(ra.EndRowIndex, rb.EndRowIndex)
+	default:
+		return nil
+	}
+
+	return &sheets.Request{
+		UpdateCells: &sheets.UpdateCellsRequest{
+			Fields: ua.Fields,
+			Range:  &merged,
+			Rows:   rows,
+		},
+	}
+}
+
+// padCopy: Returns a copy of the rows padded to the given height, with each row padded to the given width.
+// A width of 0 leaves the rows cell counts as they are.
+func padCopy(rows []*sheets.RowData, height, width int) []*sheets.RowData {
+	var result []*sheets.RowData
+	for i := 0; i < max(height, len(rows)); i++ {
+		var cells []*sheets.CellData
+		if i < len(rows) && rows[i] != nil {
+			cells = append(cells, rows[i].Values...)
+		}
+		for len(cells) < width {
+			cells = append(cells, &sheets.CellData{})
+		}
+		result = append(result, &sheets.RowData{Values: cells})
+	}
+	return result
+}
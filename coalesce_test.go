@@ -0,0 +1,132 @@
+package rwsheets
+
+import (
+	"strings"
+	"testing"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// cellUpdate: Returns an UpdateCells request writing the value to the single cell.
+func cellUpdate(gid, row, col int64, value string) *sheets.Request {
+	return updateCellsRequest(col+1, gid, col, row, []*sheets.RowData{textRow(value)}, cellFields)
+}
+
+// gridValues: Returns the values of the rows as a "|" and "," separated string.
+func gridValues(rows []*sheets.RowData) string {
+	var lines []string
+	for _, row := range rows {
+		var values []string
+		for _, cell := range row.Values {
+			values = append(values, cellString(cell))
+		}
+		lines = append(lines, strings.Join(values, ","))
+	}
+	return strings.Join(lines, "|")
+}
+
+func TestCoalesceRequestsMergeable(t *testing.T) {
+	tests := []struct {
+		name   string
+		reqs   []*sheets.Request
+		rng    string
+		values string
+	}{
+		{
+			name:   "side by side",
+			reqs:   []*sheets.Request{cellUpdate(0, 0, 0, "a"), cellUpdate(0, 0, 1, "b")},
+			rng:    "A1:B1",
+			values: "a,b",
+		},
+		{
+			name:   "right to left",
+			reqs:   []*sheets.Request{cellUpdate(0, 0, 1, "b"), cellUpdate(0, 0, 0, "a")},
+			rng:    "A1:B1",
+			values: "a,b",
+		},
+		{
+			name:   "stacked",
+			reqs:   []*sheets.Request{cellUpdate(0, 0, 0, "a"), cellUpdate(0, 1, 0, "b"), cellUpdate(0, 2, 0, "c")},
+			rng:    "A1:A3",
+			values: "a|b|c",
+		},
+		{
+			name: "block",
+			reqs: []*sheets.Request{
+				cellUpdate(0, 0, 0, "a"), cellUpdate(0, 0, 1, "b"),
+				cellUpdate(0, 1, 0, "c"), cellUpdate(0, 1, 1, "d"),
+			},
+			rng:    "A1:B2",
+			values: "a,b|c,d",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CoalesceRequests(tt.reqs)
+			if len(got) != 1 {
+				t.Fatalf("got %d requests, want 1", len(got))
+			}
+
+			update := got[0].UpdateCells
+			if rng := gridRangeToA1("", update.Range); rng != tt.rng {
+				t.Errorf("range = %s, want %s", rng, tt.rng)
+			}
+			if values := gridValues(update.Rows); values != tt.values {
+				t.Errorf("values = %s, want %s", values, tt.values)
+			}
+			if update.Fields != cellFields {
+				t.Errorf("fields = %q", update.Fields)
+			}
+		})
+	}
+}
+
+func TestCoalesceRequestsNotMergeable(t *testing.T) {
+	otherFields := cellUpdate(0, 0, 1, "b")
+	otherFields.UpdateCells.Fields = "userEnteredValue"
+
+	tests := []struct {
+		name string
+		reqs []*sheets.Request
+	}{
+		{"diagonal", []*sheets.Request{cellUpdate(0, 0, 0, "a"), cellUpdate(0, 1, 1, "b")}},
+		{"gap", []*sheets.Request{cellUpdate(0, 0, 0, "a"), cellUpdate(0, 0, 2, "b")}},
+		{"other sheet", []*sheets.Request{cellUpdate(0, 0, 0, "a"), cellUpdate(1, 0, 1, "b")}},
+		{"other fields", []*sheets.Request{cellUpdate(0, 0, 0, "a"), otherFields}},
+		{"partial edge", []*sheets.Request{
+			updateCellsRequest(1, 0, 0, 0, []*sheets.RowData{textRow("a"), textRow("b")}, cellFields),
+			cellUpdate(0, 0, 1, "c"),
+		}},
+		{"not consecutive", []*sheets.Request{
+			cellUpdate(0, 0, 0, "a"),
+			{DeleteRange: &sheets.DeleteRangeRequest{}},
+			cellUpdate(0, 0, 1, "b"),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CoalesceRequests(tt.reqs); len(got) != len(tt.reqs) {
+				t.Errorf("got %d requests, want %d", len(got), len(tt.reqs))
+			}
+		})
+	}
+}
+
+func TestCoalesceRequestsPadsRows(t *testing.T) {
+	// The first request's range is wider than its rows, so the merged rows need padding.
+	a := updateCellsRequest(2, 0, 0, 0, []*sheets.RowData{textRow("a")}, cellFields)
+	b := cellUpdate(0, 0, 2, "c")
+
+	got := CoalesceRequests([]*sheets.Request{a, b})
+	if len(got) != 1 {
+		t.Fatalf("got %d requests, want 1", len(got))
+	}
+	if values := gridValues(got[0].UpdateCells.Rows); values != "a,,c" {
+		t.Errorf("values = %s, want a,,c", values)
+	}
+	if len(a.UpdateCells.Rows[0].Values) != 1 {
+		t.Error("the original request was modified")
+	}
+}
@@ -0,0 +1,85 @@
+package rwsheets
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+var (
+	ErrInvalidColor = errors.New("invalid hex color, expected #RRGGBB or #RRGGBBAA")
+)
+
+// ColorRGB: Creates a new Sheets ColorStyle with the given red, green and blue values from 0 to 1, fully opaque.
+func ColorRGB(r, g, b float64) *sheets.ColorStyle {
+	return ColorRGBA(r, g, b, 1)
+}
+
+// ColorRGBA: Creates a new Sheets ColorStyle with the given red, green, blue and alpha values from 0 to 1.
+func ColorRGBA(r, g, b, a float64) *sheets.ColorStyle {
+	return &sheets.ColorStyle{
+		RgbColor: &sheets.Color{
+			Alpha: a,
+			Blue:  b,
+			Green: g,
+			Red:   r,
+		},
+	}
+}
+
+// ColorHex: Creates a new Sheets ColorStyle from a hex color like "#FF9900", or "#FF990080" with an alpha.
+// Returns ErrInvalidColor if the string isn't a # followed by 6 or 8 hex digits.
+func ColorHex(hex string) (*sheets.ColorStyle, error) {
+	if (len(hex) != 7 && len(hex) != 9) || hex[0] != '#' {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidColor, hex)
+	}
+
+	v, err := strconv.ParseUint(hex[1:], 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidColor, hex)
+	}
+
+	alpha := uint64(0xFF)
+	if len(hex) == 9 {
+		alpha = v & 0xFF
+		v >>= 8
+	}
+
+	component := func(c uint64) float64 {
+		return float64(c&0xFF) / 255
+	}
+	return ColorRGBA(component(v>>16), component(v>>8), component(v), component(alpha)), nil
+}
+
+// CellBackgroundHex: Returns the cells effective background color as "#RRGGBB", or "" if it isn't set.
+// Theme colors can't be resolved from the cell alone, so they are also returned as "".
+//
+// The cell must be read with the "effectiveFormat.backgroundColorStyle" cell field, which
+// GetSheetData includes, for example "sheets(data(rowData(values(effectiveFormat.backgroundColorStyle))))".
+func CellBackgroundHex(c *sheets.CellData) string {
+	if c == nil || c.EffectiveFormat == nil {
+		return ""
+	}
+
+	format := c.EffectiveFormat
+	if format.BackgroundColorStyle != nil {
+		return colorHex(format.BackgroundColorStyle.RgbColor)
+	}
+	return colorHex(format.BackgroundColor)
+}
+
+// colorHex: Returns the color as "#RRGGBB", or "" if it is nil.
+func colorHex(c *sheets.Color) string {
+	if c == nil {
+		return ""
+	}
+	return fmt.Sprintf("#%02X%02X%02X", colorByte(c.Red), colorByte(c.Green), colorByte(c.Blue))
+}
+
+// colorByte: Converts a 0 to 1 color component to a 0 to 255 byte.
+func colorByte(v float64) uint8 {
+	return uint8(math.Round(math.Max(0, math.Min(1, v)) * 255))
+}
@@ -0,0 +1,93 @@
+package rwsheets
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestCellBackgroundHex(t *testing.T) {
+	orange := &sheets.Color{Red: 1, Green: 0.6, Blue: 0}
+
+	tests := []struct {
+		name string
+		cell *sheets.CellData
+		want string
+	}{
+		{"nil cell", nil, ""},
+		{"no format", &sheets.CellData{}, ""},
+		{"unset", &sheets.CellData{EffectiveFormat: &sheets.CellFormat{}}, ""},
+		{"color style", &sheets.CellData{EffectiveFormat: &sheets.CellFormat{
+			BackgroundColorStyle: &sheets.ColorStyle{RgbColor: orange},
+		}}, "#FF9900"},
+		{"theme color", &sheets.CellData{EffectiveFormat: &sheets.CellFormat{
+			BackgroundColorStyle: &sheets.ColorStyle{ThemeColor: "ACCENT1"},
+		}}, ""},
+		{"deprecated color", &sheets.CellData{EffectiveFormat: &sheets.CellFormat{
+			BackgroundColor: &sheets.Color{Red: 1, Green: 1, Blue: 1},
+		}}, "#FFFFFF"},
+		// Components the API leaves out are zero.
+		{"black", &sheets.CellData{EffectiveFormat: &sheets.CellFormat{BackgroundColor: &sheets.Color{}}}, "#000000"},
+		{"clamped", &sheets.CellData{EffectiveFormat: &sheets.CellFormat{BackgroundColor: &sheets.Color{Red: 2, Blue: -1}}}, "#FF0000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CellBackgroundHex(tt.cell); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorRGB(t *testing.T) {
+	c := ColorRGB(1, 0.6, 0.2).RgbColor
+	if c.Red != 1 || c.Green != 0.6 || c.Blue != 0.2 || c.Alpha != 1 {
+		t.Errorf("ColorRGB = %+v", c)
+	}
+
+	c = ColorRGBA(0.1, 0.2, 0.3, 0.4).RgbColor
+	if c.Red != 0.1 || c.Green != 0.2 || c.Blue != 0.3 || c.Alpha != 0.4 {
+		t.Errorf("ColorRGBA = %+v", c)
+	}
+
+	// The old constructor takes alpha, blue, green, red.
+	if got := Color(0.4, 0.3, 0.2, 0.1).RgbColor; !reflect.DeepEqual(got, c) {
+		t.Errorf("Color = %+v, want %+v", got, c)
+	}
+}
+
+func TestColorHex(t *testing.T) {
+	tests := []struct {
+		hex  string
+		want sheets.Color
+	}{
+		{"#FF9900", sheets.Color{Red: 1, Green: 0.6, Blue: 0, Alpha: 1}},
+		{"#ff9900", sheets.Color{Red: 1, Green: 0.6, Blue: 0, Alpha: 1}},
+		{"#000000", sheets.Color{Alpha: 1}},
+		{"#33669900", sheets.Color{Red: 0.2, Green: 0.4, Blue: 0.6, Alpha: 0}},
+		{"#FFFFFF33", sheets.Color{Red: 1, Green: 1, Blue: 1, Alpha: 0.2}},
+	}
+
+	for _, tt := range tests {
+		c, err := ColorHex(tt.hex)
+		if err != nil {
+			t.Fatalf("ColorHex(%q): %v", tt.hex, err)
+		}
+		if !reflect.DeepEqual(*c.RgbColor, tt.want) {
+			t.Errorf("ColorHex(%q) = %+v, want %+v", tt.hex, *c.RgbColor, tt.want)
+		}
+		if got := colorHex(c.RgbColor); !strings.EqualFold(got, tt.hex[:7]) {
+			t.Errorf("colorHex round trip of %q = %q", tt.hex, got)
+		}
+	}
+
+	for _, hex := range []string{"", "#", "FF9900", "#FF990", "#FF99000", "#GG9900", "#+F9900", " #FF9900", "#FF9900FF00"} {
+		if _, err := ColorHex(hex); !errors.Is(err, ErrInvalidColor) {
+			t.Errorf("ColorHex(%q) err = %v, want ErrInvalidColor", hex, err)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package rwsheets
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// CellsEqual: Reports whether the two cells have the same user entered value and formatting.
+// Computed fields like EffectiveValue, EffectiveFormat and FormattedValue are ignored.
+func CellsEqual(a, b *sheets.CellData) bool {
+	return len(CellDiff(a, b)) == 0
+}
+
+// CellDiff: Returns a human readable description of each difference between the two cells.
+// Only the user entered value, user entered format, note, data validation and text format runs are compared.
+func CellDiff(a, b *sheets.CellData) []string {
+	if a == nil {
+		a = &sheets.CellData{}
+	}
+	if b == nil {
+		b = &sheets.CellData{}
+	}
+
+	var diffs []string
+	if av, bv := toJSON(a.UserEnteredValue), toJSON(b.UserEnteredValue); av != bv {
+		diffs = append(diffs, fmt.Sprintf("userEnteredValue: %s != %s", av, bv))
+	}
+	diffs = append(diffs, formatDiff(a.UserEnteredFormat, b.UserEnteredFormat)...)
+	if a.Note != b.Note {
+		diffs = append(diffs, fmt.Sprintf("note: %q != %q", a.Note, b.Note))
+	}
+	if av, bv := toJSON(a.DataValidation), toJSON(b.DataValidation); av != bv {
+		diffs = append(diffs, fmt.Sprintf("dataValidation: %s != %s", av, bv))
+	}
+	if av, bv := toJSON(a.TextFormatRuns), toJSON(b.TextFormatRuns); av != bv {
+		diffs = append(diffs, fmt.Sprintf("textFormatRuns: %s != %s", av, bv))
+	}
+
+	return diffs
+}
+
+// formatDiff: Compares the two cell formats field by field.
+func formatDiff(a, b *sheets.CellFormat) []string {
+	af := toJSONFields(a)
+	bf := toJSONFields(b)
+
+	keys := make(map[string]bool)
+	for k := range af {
+		keys[k] = true
+	}
+	for k := range bf {
+		keys[k] = true
+	}
+
+	var sorted []string
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var diffs []string
+	for _, k := range sorted {
+		av, bv := string(af[k]), string(bf[k])
+		if av == "" {
+			av = "null"
+		}
+		if bv == "" {
+			bv = "null"
+		}
+		if av != bv {
+			diffs = append(diffs, fmt.Sprintf("userEnteredFormat.%s: %s != %s", k, av, bv))
+		}
+	}
+	return diffs
+}
+
+// toJSON: Returns the JSON encoding of the value, or "null" if it can't be encoded.
+func toJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "null"
+	}
+	return string(b)
+}
+
+// toJSONFields: Returns the JSON encoding of each field set on the value.
+func toJSONFields(v interface{}) map[string]json.RawMessage {
+	fields := make(map[string]json.RawMessage)
+	json.Unmarshal([]byte(toJSON(v)), &fields)
+	return fields
+}
+
+// CellChange: struct describing a cell that differs between two sets of rows.
+type CellChange struct {
+	Row  int // Zero-based index of the row within the rows.
+	Col  int // Zero-based index of the cell within the row.
+	Cell *sheets.CellData
+}
+
+// DiffRows: Returns the cells in newRows that differ from the same position in oldRows, in row order.
+// Cells are compared using CellsEqual, and missing cells are treated as empty.
+func DiffRows(oldRows, newRows []*sheets.RowData) []CellChange {
+	var changes []CellChange
+	for i, row := range newRows {
+		if row == nil {
+			continue
+		}
+
+		var old *sheets.RowData
+		if i < len(oldRows) {
+			old = oldRows[i]
+		}
+		for j, cell := range row.Values {
+			if !CellsEqual(cellAt(old, j), cell) {
+				changes = append(changes, CellChange{Row: i, Col: j, Cell: cell})
+			}
+		}
+	}
+	return changes
+}
+
+// cloneCell: Returns a deep copy of the cell.
+func cloneCell(c *sheets.CellData) *sheets.CellData {
+	if c == nil {
+		return nil
+	}
+
+	var clone sheets.CellData
+	if err := json.Unmarshal([]byte(toJSON(c)), &clone); err != nil {
+		return nil
+	}
+	return &clone
+}
@@ -0,0 +1,48 @@
+package rwsheets
+
+import (
+	"strings"
+	"testing"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestCellsEqual(t *testing.T) {
+	s := NewStyler()
+
+	a := s.NumberCell(1.5, RIGHT_BORDER)
+	b := s.NumberCell(1.5, RIGHT_BORDER)
+	// Computed fields are ignored.
+	b.EffectiveValue = NumberValue(1.5)
+	b.FormattedValue = "1.50"
+
+	if !CellsEqual(a, b) {
+		t.Errorf("cells differ: %v", CellDiff(a, b))
+	}
+	if !CellsEqual(nil, &sheets.CellData{}) {
+		t.Error("nil and empty cells should be equal")
+	}
+}
+
+func TestCellDiffValue(t *testing.T) {
+	s := NewStyler()
+
+	diffs := CellDiff(s.TextCell("a", nil), s.TextCell("b", nil))
+	if len(diffs) != 1 || !strings.HasPrefix(diffs[0], "userEnteredValue:") {
+		t.Errorf("diffs = %v, want a single value difference", diffs)
+	}
+}
+
+func TestCellDiffFormat(t *testing.T) {
+	a := NewStyler().TextCell("a", nil)
+	b := NewStyler().FontSize(12).HorizontalAlignment("RIGHT").TextCell("a", nil)
+
+	diffs := CellDiff(a, b)
+	want := []string{
+		`userEnteredFormat.horizontalAlignment: "LEFT" != "RIGHT"`,
+		`userEnteredFormat.textFormat: {"fontFamily":"Verdana","fontSize":10} != {"fontFamily":"Verdana","fontSize":12}`,
+	}
+	if strings.Join(diffs, "\n") != strings.Join(want, "\n") {
+		t.Errorf("diffs = %q, want %q", diffs, want)
+	}
+}
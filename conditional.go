@@ -0,0 +1,45 @@
+package rwsheets
+
+import (
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// ColorScaleRule: Creates a new conditional format color scale (gradient) rule for the given range.
+// The midpoint is optional and is placed at the 50th percentile when it is set.
+func ColorScaleRule(gr *sheets.GridRange, minColor, midColor, maxColor *sheets.ColorStyle) *sheets.ConditionalFormatRule {
+	gradient := sheets.GradientRule{
+		Minpoint: &sheets.InterpolationPoint{
+			ColorStyle: minColor,
+			Type:       "MIN",
+		},
+		Maxpoint: &sheets.InterpolationPoint{
+			ColorStyle: maxColor,
+			Type:       "MAX",
+		},
+	}
+	if midColor != nil {
+		gradient.Midpoint = &sheets.InterpolationPoint{
+			ColorStyle: midColor,
+			Type:       "PERCENTILE",
+			Value:      "50",
+		}
+	}
+
+	return &sheets.ConditionalFormatRule{
+		GradientRule: &gradient,
+		Ranges:       []*sheets.GridRange{gr},
+	}
+}
+
+// AddColorScale: Adds the color scale rule to the sheet as its first conditional format rule.
+func AddColorScale(ssid string, rule *sheets.ConditionalFormatRule, srv *sheets.Service) error {
+	request := sheets.Request{
+		AddConditionalFormatRule: &sheets.AddConditionalFormatRuleRequest{
+			Index: 0,
+			Rule:  rule,
+		},
+	}
+
+	_, err := batchUpdate(ssid, []*sheets.Request{&request}, srv)
+	return err
+}
@@ -0,0 +1,51 @@
+package rwsheets
+
+import (
+	"testing"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestColorScaleRule(t *testing.T) {
+	red, yellow, green := Color(1, 0, 0, 1), Color(1, 0, 1, 1), Color(1, 0, 1, 0)
+	gr := &sheets.GridRange{SheetId: 1, StartRowIndex: 1, EndRowIndex: 20}
+
+	rule := ColorScaleRule(gr, red, yellow, green)
+	if len(rule.Ranges) != 1 || rule.Ranges[0] != gr {
+		t.Errorf("ranges = %+v, want the given range", rule.Ranges)
+	}
+
+	gradient := rule.GradientRule
+	if p := gradient.Minpoint; p.Type != "MIN" || p.ColorStyle != red {
+		t.Errorf("minpoint = %+v", p)
+	}
+	if p := gradient.Midpoint; p == nil || p.Type != "PERCENTILE" || p.Value != "50" || p.ColorStyle != yellow {
+		t.Errorf("midpoint = %+v", p)
+	}
+	if p := gradient.Maxpoint; p.Type != "MAX" || p.ColorStyle != green {
+		t.Errorf("maxpoint = %+v", p)
+	}
+
+	if rule := ColorScaleRule(gr, red, nil, green); rule.GradientRule.Midpoint != nil {
+		t.Errorf("midpoint = %+v, want none", rule.GradientRule.Midpoint)
+	}
+}
+
+func TestAddColorScale(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	rule := ColorScaleRule(&sheets.GridRange{SheetId: 1}, Color(1, 0, 0, 1), nil, Color(1, 0, 1, 0))
+	if err := AddColorScale("ssid", rule, srv); err != nil {
+		t.Fatalf("AddColorScale: %v", err)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 1 || requests[0].AddConditionalFormatRule == nil {
+		t.Fatalf("requests = %+v, want a single AddConditionalFormatRule", requests)
+	}
+
+	got := requests[0].AddConditionalFormatRule.Rule.GradientRule
+	if got.Minpoint.ColorStyle.RgbColor.Red != 1 || got.Maxpoint.ColorStyle.RgbColor.Green != 1 {
+		t.Errorf("gradient = %+v", got)
+	}
+}
@@ -0,0 +1,109 @@
+package rwsheets
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// debounceKey identifies a single cell in a spreadsheet.
+type debounceKey struct {
+	gid, row, col int64
+}
+
+// DebouncedWriter is to be used to batch up rapid single cell writes.
+// Writes to the same cell within the window are coalesced so only the latest value is sent,
+// and everything pending is written in a single batch update when the window ends or Flush is called.
+type DebouncedWriter struct {
+	ssid   string
+	srv    *sheets.Service
+	window time.Duration
+
+	// flushMu serializes flushes, so an older batch can't be written after a newer one.
+	flushMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[debounceKey]*sheets.CellData
+	timer   *time.Timer
+	err     error
+}
+
+// NewDebouncedWriter: Returns a new debounced writer for the spreadsheet that flushes pending writes
+// once the window has passed since the first of them.
+func NewDebouncedWriter(ssid string, window time.Duration, srv *sheets.Service) *DebouncedWriter {
+	return &DebouncedWriter{
+		ssid:    ssid,
+		srv:     srv,
+		window:  window,
+		pending: make(map[debounceKey]*sheets.CellData),
+	}
+}
+
+// SetCell: Queues a write of the cell at the zero-based row and column of the sheet,
+// replacing any pending write to the same cell.
+func (w *DebouncedWriter) SetCell(gid, row, col int64, cell *sheets.CellData) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending[debounceKey{gid, row, col}] = cell
+	if w.timer == nil {
+		w.timer = time.AfterFunc(w.window, func() {
+			if err := w.Flush(); err != nil {
+				w.mu.Lock()
+				w.err = err
+				w.mu.Unlock()
+			}
+		})
+	}
+}
+
+// Flush: Writes all pending cells now.
+// Returns the error from this write, or otherwise from the last timed flush if it failed.
+// Flushes run one at a time, so pending cells are always written in the order they were queued.
+func (w *DebouncedWriter) Flush() error {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	w.mu.Lock()
+	pending := w.pending
+	w.pending = make(map[debounceKey]*sheets.CellData)
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	err := w.err
+	w.err = nil
+	w.mu.Unlock()
+
+	if len(pending) == 0 {
+		return err
+	}
+
+	// Sort the cells so neighbouring writes can be coalesced.
+	var keys []debounceKey
+	for key := range pending {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].gid != keys[j].gid {
+			return keys[i].gid < keys[j].gid
+		}
+		if keys[i].row != keys[j].row {
+			return keys[i].row < keys[j].row
+		}
+		return keys[i].col < keys[j].col
+	})
+
+	var requests []*sheets.Request
+	for _, key := range keys {
+		rows := []*sheets.RowData{{Values: []*sheets.CellData{pending[key]}}}
+		requests = append(requests, updateCellsRequest(key.col+1, key.gid, key.col, key.row, rows, cellFields))
+	}
+
+	if _, flushErr := batchUpdate(w.ssid, CoalesceRequests(requests), w.srv); flushErr != nil {
+		return flushErr
+	}
+	return err
+}
@@ -0,0 +1,125 @@
+package rwsheets
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestDebouncedWriterCoalescesWrites(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+	w := NewDebouncedWriter("ssid", time.Hour, srv)
+
+	for _, value := range []string{"first", "second", "third"} {
+		w.SetCell(2, 4, 1, &sheets.CellData{UserEnteredValue: TextValue(value)})
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 1 || requests[0].UpdateCells == nil {
+		t.Fatalf("requests = %+v, want a single UpdateCells", requests)
+	}
+
+	update := requests[0].UpdateCells
+	if got := gridRangeToA1("", update.Range); got != "B5:B5" {
+		t.Errorf("range = %s, want B5:B5", got)
+	}
+	if got := gridValues(update.Rows); got != "third" {
+		t.Errorf("value = %s, want the last write", got)
+	}
+
+	// Nothing is pending, so another flush doesn't call the API.
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if got := len(api.BatchUpdates()); got != 1 {
+		t.Errorf("got %d batch updates, want 1", got)
+	}
+}
+
+func TestDebouncedWriterWindow(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+	w := NewDebouncedWriter("ssid", 10*time.Millisecond, srv)
+
+	w.SetCell(0, 0, 0, &sheets.CellData{UserEnteredValue: TextValue("a")})
+	w.SetCell(0, 0, 1, &sheets.CellData{UserEnteredValue: TextValue("b")})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(api.Calls()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 1 || gridValues(requests[0].UpdateCells.Rows) != "a,b" {
+		t.Errorf("requests = %+v, want the neighbouring cells in one UpdateCells", requests)
+	}
+}
+
+func TestDebouncedWriterSerializesFlushes(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+
+	var once sync.Once
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+
+		// Hold the first write until the second flush has been started.
+		once.Do(func() {
+			close(entered)
+			<-release
+		})
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return http.StatusOK, map[string]interface{}{}
+	})
+	w := NewDebouncedWriter("ssid", time.Hour, srv)
+
+	var wg sync.WaitGroup
+	flush := func() {
+		defer wg.Done()
+		if err := w.Flush(); err != nil {
+			t.Errorf("Flush: %v", err)
+		}
+	}
+
+	w.SetCell(0, 0, 0, &sheets.CellData{UserEnteredValue: TextValue("old")})
+	wg.Add(1)
+	go flush()
+	<-entered
+
+	w.SetCell(0, 0, 0, &sheets.CellData{UserEnteredValue: TextValue("new")})
+	wg.Add(1)
+	go flush()
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if maxInFlight != 1 {
+		t.Errorf("%d writes were in flight at once, want 1", maxInFlight)
+	}
+
+	updates := api.BatchUpdates()
+	if len(updates) != 2 {
+		t.Fatalf("got %d batch updates, want 2", len(updates))
+	}
+	for i, want := range []string{"old", "new"} {
+		if got := gridValues(updates[i].Requests[0].UpdateCells.Rows); !strings.Contains(got, want) {
+			t.Errorf("update %d = %s, want %s", i, got, want)
+		}
+	}
+}
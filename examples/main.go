@@ -42,7 +42,7 @@ func main() {
 		log.Fatalf("failed to read sample data from file - %s", err.Error())
 	}
 
-	styler := rwsheets.NewStyler().FontBold(true).FontFamily("Verdana").FontSize(int64(12)).HorizontalAlignment("CENTER").VerticalAlignment("MIDDLE")
+	headerStyler := rwsheets.NewStyler().FontBold(true).FontFamily("Verdana").FontSize(int64(12)).HorizontalAlignment("CENTER").VerticalAlignment("MIDDLE")
 
 	headerBorders := rwsheets.BorderConf{
 		Bottom: true,
@@ -51,7 +51,7 @@ func main() {
 		Style:  "SOLID_MEDIUM",
 		Top:    true,
 	}
-	newRows := styler.CreateHeaderRow(data.Headers, &headerBorders)
+	newRows := headerStyler.CreateHeaderRow(data.Headers, &headerBorders)
 
 	cellBorders := rwsheets.BorderConf{
 		Bottom: true,
@@ -61,18 +61,20 @@ func main() {
 		Top:    false,
 	}
 	dateLayout := "2006-01-02"
-	styler.FontSize(int64(10)).FontBold(false)
+
+	// The With methods return copies, so each column's alignment doesn't leak into the next.
+	styler := headerStyler.WithFontSize(int64(10)).FontBold(false).DatePattern("M/d/yyyy")
 
 	for i, invoice := range data.Invoices {
 		if i > 0 {
 			cellBorders.Top = true
 		}
 		var cells []*sheets.CellData
-		cells = append(cells, styler.HorizontalAlignment("LEFT").TextCell(invoice.Customer, &cellBorders))
-		cells = append(cells, styler.HorizontalAlignment("CENTER").TextCell(invoice.Invoice, &cellBorders))
-		cells = append(cells, styler.HorizontalAlignment("RIGHT").AccountingCell(invoice.Amount, &cellBorders))
-		cells = append(cells, styler.HorizontalAlignment("RIGHT").DatePattern("M/d/yyyy").DateCell(invoice.Date, dateLayout, &cellBorders))
-		cells = append(cells, styler.HorizontalAlignment("CENTER").CheckBoxCell(invoice.Paid, &cellBorders))
+		cells = append(cells, styler.WithAlignment("LEFT").TextCell(invoice.Customer, &cellBorders))
+		cells = append(cells, styler.WithAlignment("CENTER").TextCell(invoice.Invoice, &cellBorders))
+		cells = append(cells, styler.WithAlignment("RIGHT").AccountingCell(invoice.Amount, &cellBorders))
+		cells = append(cells, styler.WithAlignment("RIGHT").DateCell(invoice.Date, dateLayout, &cellBorders))
+		cells = append(cells, styler.WithAlignment("CENTER").CheckBoxCell(invoice.Paid, &cellBorders))
 
 		row := sheets.RowData{
 			Values: cells,
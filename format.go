@@ -0,0 +1,191 @@
+package rwsheets
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// StyleColumn: Formats the zero-based column of the sheet with the header stylers format for the first
+// headerRows rows, and the body stylers format for every row after them, in a single batch update.
+//
+// The stylers formats replace the cells whole user entered format, including any number format.
+// A nil styler leaves its part of the column unchanged.
+func StyleColumn(ssid string, gid int64, columnIndex int64, headerStyler, bodyStyler *Styler, headerRows int64, srv *sheets.Service) error {
+	if columnIndex < 0 || headerRows < 0 {
+		return fmt.Errorf("%w: column %d with %d header rows", ErrInvalidRange, columnIndex, headerRows)
+	}
+
+	column := func(startRow, endRow int64) *sheets.GridRange {
+		return &sheets.GridRange{
+			SheetId:          gid,
+			StartRowIndex:    startRow,
+			EndRowIndex:      endRow,
+			StartColumnIndex: columnIndex,
+			EndColumnIndex:   columnIndex + 1,
+		}
+	}
+
+	var requests []*sheets.Request
+	if headerStyler != nil && headerRows > 0 {
+		requests = append(requests, repeatFormatRequest(column(0, headerRows), headerStyler.cellFormat(nil, nil)))
+	}
+	if bodyStyler != nil {
+		// An end row of 0 leaves the range unbounded, so it covers the rest of the column.
+		requests = append(requests, repeatFormatRequest(column(headerRows, 0), bodyStyler.cellFormat(nil, nil)))
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+
+	_, err := batchUpdate(ssid, requests, srv)
+	return err
+}
+
+// ClearFormatting: Resets the formatting of the cells from the zero-based startRow and startCol up to,
+// but not including, endRow and endCol, keeping their values. Use ClearRange to clear the values instead.
+func ClearFormatting(ssid string, gid, startRow, endRow, startCol, endCol int64, srv *sheets.Service) error {
+	if startRow < 0 || startCol < 0 || startRow >= endRow || startCol >= endCol {
+		return fmt.Errorf("%w: rows %d to %d, columns %d to %d", ErrInvalidRange, startRow, endRow, startCol, endCol)
+	}
+
+	// Updating the format field without any rows clears it for every cell in the range.
+	request := sheets.Request{
+		UpdateCells: &sheets.UpdateCellsRequest{
+			Fields: "userEnteredFormat",
+			Range: &sheets.GridRange{
+				EndColumnIndex:   endCol,
+				EndRowIndex:      endRow,
+				SheetId:          gid,
+				StartColumnIndex: startCol,
+				StartRowIndex:    startRow,
+			},
+		},
+	}
+
+	_, err := batchUpdate(ssid, []*sheets.Request{&request}, srv)
+	return err
+}
+
+// GetEffectiveFormats: Retrieve the effective format of every cell in the range, keyed by its A1 reference like "B3".
+// The effective format is what Sheets actually displays, including number formats from the cells type,
+// so exports can render values the same way. Cells without an effective format are left out.
+//
+// Only the formats are requested, using the "sheets(data(startRow,startColumn,rowData(values(effectiveFormat))))" fields mask.
+func GetEffectiveFormats(ssid, readRange string, srv *sheets.Service) (map[string]*sheets.CellFormat, error) {
+	formats := make(map[string]*sheets.CellFormat)
+
+	ss, err := srv.Spreadsheets.Get(ssid).Ranges(readRange).
+		Fields("sheets(data(startRow,startColumn,rowData(values(effectiveFormat))))").Do()
+	if err != nil {
+		return formats, err
+	}
+
+	grid, err := firstGrid(ss)
+	if err != nil {
+		return formats, err
+	}
+
+	for i, row := range grid.RowData {
+		if row == nil {
+			continue
+		}
+		for j, cell := range row.Values {
+			if cell == nil || cell.EffectiveFormat == nil {
+				continue
+			}
+
+			ref := CellRef(grid.StartRow+int64(i), grid.StartColumn+int64(j))
+			formats[ref] = cell.EffectiveFormat
+		}
+	}
+
+	return formats, nil
+}
+
+// DetectColumnFormat: Guesses the number format for the column from the string values of its cells,
+// for formatting columns of imported text. Empty cells are skipped, so pass the rows without any header row.
+//
+// The column is CURRENCY if every value contains "$", PERCENT if every value ends in "%", DATE if every value is
+// a date like "1/2/2006" or "2006-01-02", NUMBER if every value is a plain number and TEXT otherwise.
+// Returns nil if the column has no values.
+func DetectColumnFormat(rows []*sheets.RowData, columnIndex int) *sheets.NumberFormat {
+	var values []string
+	for _, row := range rows {
+		if value := strings.TrimSpace(cellString(cellAt(row, columnIndex))); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	all := func(match func(value string) bool) bool {
+		for _, value := range values {
+			if !match(value) {
+				return false
+			}
+		}
+		return true
+	}
+	isNumber := func(value string) bool {
+		_, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", ""), 64)
+		return err == nil
+	}
+	isDate := func(layout string) func(value string) bool {
+		return func(value string) bool {
+			_, err := time.Parse(layout, value)
+			return err == nil
+		}
+	}
+
+	switch {
+	case all(func(value string) bool {
+		_, err := parseFormattedNumber(value)
+		return strings.Contains(value, "$") && err == nil
+	}):
+		return &sheets.NumberFormat{Pattern: `"$"#,##0.00`, Type: "CURRENCY"}
+	case all(func(value string) bool {
+		return strings.HasSuffix(value, "%") && isNumber(strings.TrimSuffix(value, "%"))
+	}):
+		return &sheets.NumberFormat{Pattern: "0.00%", Type: "PERCENT"}
+	case all(isDate("1/2/2006")):
+		return &sheets.NumberFormat{Pattern: "M/d/yyyy", Type: "DATE"}
+	case all(isDate("2006-01-02")):
+		return &sheets.NumberFormat{Pattern: "yyyy-MM-dd", Type: "DATE"}
+	case all(isNumber):
+		return &sheets.NumberFormat{Pattern: "#,##0.00", Type: "NUMBER"}
+	}
+	return &sheets.NumberFormat{Type: "TEXT"}
+}
+
+// ApplyNumberFormat: Sets the number format of every cell in the range, keeping the rest of their formatting.
+// Pair it with DetectColumnFormat to format imported columns.
+func ApplyNumberFormat(ssid string, gr *sheets.GridRange, nf *sheets.NumberFormat, srv *sheets.Service) error {
+	request := sheets.Request{
+		RepeatCell: &sheets.RepeatCellRequest{
+			Cell: &sheets.CellData{
+				UserEnteredFormat: &sheets.CellFormat{NumberFormat: nf},
+			},
+			Fields: "userEnteredFormat.numberFormat",
+			Range:  gr,
+		},
+	}
+
+	_, err := batchUpdate(ssid, []*sheets.Request{&request}, srv)
+	return err
+}
+
+// repeatFormatRequest: Creates the request for setting the user entered format of every cell in the range.
+func repeatFormatRequest(gr *sheets.GridRange, format *sheets.CellFormat) *sheets.Request {
+	return &sheets.Request{
+		RepeatCell: &sheets.RepeatCellRequest{
+			Cell:   &sheets.CellData{UserEnteredFormat: format},
+			Fields: "userEnteredFormat",
+			Range:  gr,
+		},
+	}
+}
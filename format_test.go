@@ -0,0 +1,208 @@
+package rwsheets
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestStyleColumn(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	header := NewStyler().FontBold(true).HorizontalAlignment("CENTER")
+	body := NewStyler().FontSize(9)
+
+	if err := StyleColumn("ssid", 3, 2, header, body, 2, srv); err != nil {
+		t.Fatalf("StyleColumn: %v", err)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 2 || requests[0].RepeatCell == nil || requests[1].RepeatCell == nil {
+		t.Fatalf("requests = %+v, want two RepeatCell requests", requests)
+	}
+
+	head, rest := requests[0].RepeatCell, requests[1].RepeatCell
+	if got := gridRangeToA1("Sheet", head.Range); got != "Sheet!C1:C2" || head.Range.SheetId != 3 {
+		t.Errorf("header range = %s on sheet %d, want Sheet!C1:C2 on sheet 3", got, head.Range.SheetId)
+	}
+	// The body runs from the first row after the header to the end of the column.
+	if r := rest.Range; r.SheetId != 3 || r.StartRowIndex != 2 || r.EndRowIndex != 0 || r.StartColumnIndex != 2 || r.EndColumnIndex != 3 {
+		t.Errorf("body range = %+v, want C3 to the end of column C on sheet 3", r)
+	}
+
+	if f := head.Cell.UserEnteredFormat; !f.TextFormat.Bold || f.HorizontalAlignment != "CENTER" {
+		t.Errorf("header format = %+v", f)
+	}
+	if f := rest.Cell.UserEnteredFormat; f.TextFormat.Bold || f.TextFormat.FontSize != 9 {
+		t.Errorf("body format = %+v", f)
+	}
+	for _, req := range requests {
+		if req.RepeatCell.Fields != "userEnteredFormat" {
+			t.Errorf("fields = %q", req.RepeatCell.Fields)
+		}
+	}
+}
+
+func TestStyleColumnNoHeader(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	if err := StyleColumn("ssid", 0, 0, NewStyler(), NewStyler(), 0, srv); err != nil {
+		t.Fatalf("StyleColumn: %v", err)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 1 || gridRangeToA1("Sheet", requests[0].RepeatCell.Range) != "Sheet!A:A" {
+		t.Errorf("requests = %+v, want only the whole column body", requests)
+	}
+}
+
+func TestStyleColumnInvalid(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	if err := StyleColumn("ssid", 0, -1, nil, NewStyler(), 1, srv); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("err = %v, want ErrInvalidRange", err)
+	}
+	if err := StyleColumn("ssid", 0, 0, nil, nil, 1, srv); err != nil {
+		t.Errorf("no stylers err = %v", err)
+	}
+	if len(api.Calls()) != 0 {
+		t.Errorf("got %d calls, want none", len(api.Calls()))
+	}
+}
+
+func TestClearFormatting(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	if err := ClearFormatting("ssid", 2, 1, 10, 0, 4, srv); err != nil {
+		t.Fatalf("ClearFormatting: %v", err)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 1 || requests[0].UpdateCells == nil {
+		t.Fatalf("requests = %+v, want a single UpdateCells", requests)
+	}
+
+	req := requests[0].UpdateCells
+	if req.Fields != "userEnteredFormat" || len(req.Rows) != 0 {
+		t.Errorf("request = %+v, want only the format field without rows", req)
+	}
+	if got := gridRangeToA1("", req.Range); got != "A2:D10" || req.Range.SheetId != 2 {
+		t.Errorf("range = %s on sheet %d, want A2:D10 on sheet 2", got, req.Range.SheetId)
+	}
+}
+
+func TestClearFormattingInvalid(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	if err := ClearFormatting("ssid", 0, 5, 5, 0, 1, srv); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("empty rows err = %v, want ErrInvalidRange", err)
+	}
+	if err := ClearFormatting("ssid", 0, 0, 1, 3, 2, srv); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("reversed columns err = %v, want ErrInvalidRange", err)
+	}
+	if len(api.Calls()) != 0 {
+		t.Error("invalid ranges called the API")
+	}
+}
+
+func TestGetEffectiveFormats(t *testing.T) {
+	currency := &sheets.CellFormat{NumberFormat: &sheets.NumberFormat{Type: "CURRENCY", Pattern: `"$"#,##0.00`}}
+	date := &sheets.CellFormat{NumberFormat: &sheets.NumberFormat{Type: "DATE", Pattern: "M/d/yyyy"}}
+
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		// C2:D3, with the currency in C2 and the date in D3.
+		return http.StatusOK, gridResponse(1, 2,
+			&sheets.RowData{Values: []*sheets.CellData{{EffectiveFormat: currency}, {}}},
+			&sheets.RowData{Values: []*sheets.CellData{{}, {EffectiveFormat: date}}},
+		)
+	})
+
+	formats, err := GetEffectiveFormats("ssid", "Report!C2:D3", srv)
+	if err != nil {
+		t.Fatalf("GetEffectiveFormats: %v", err)
+	}
+
+	if len(formats) != 2 {
+		t.Fatalf("formats = %v, want 2 formats", formats)
+	}
+	if f := formats["C2"]; f == nil || f.NumberFormat.Type != "CURRENCY" {
+		t.Errorf("C2 = %+v, want the currency format", f)
+	}
+	if f := formats["D3"]; f == nil || f.NumberFormat.Pattern != "M/d/yyyy" {
+		t.Errorf("D3 = %+v, want the date format", f)
+	}
+
+	call := api.Calls()[0]
+	if got := call.Query.Get("fields"); got != "sheets(data(startRow,startColumn,rowData(values(effectiveFormat))))" {
+		t.Errorf("fields = %q", got)
+	}
+	if got := call.Query.Get("ranges"); got != "Report!C2:D3" {
+		t.Errorf("ranges = %q", got)
+	}
+}
+
+func TestDetectColumnFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		typ     string
+		pattern string
+	}{
+		{"currency", []string{"$1,234.50", "$ (12.00)", "$0"}, "CURRENCY", `"$"#,##0.00`},
+		{"percent", []string{"12.5%", "100%", "-3%"}, "PERCENT", "0.00%"},
+		{"date", []string{"3/7/2024", "12/31/2023"}, "DATE", "M/d/yyyy"},
+		{"iso date", []string{"2024-03-07", "2023-12-31"}, "DATE", "yyyy-MM-dd"},
+		{"number", []string{"1,234.5", "-12", "3e2"}, "NUMBER", "#,##0.00"},
+		{"mixed", []string{"$12", "40%"}, "TEXT", ""},
+		{"text", []string{"apple", "12"}, "TEXT", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The value is in the second column, with an empty cell between the values.
+			var rows []*sheets.RowData
+			for _, value := range tt.values {
+				rows = append(rows, textRow("key", value), textRow("key", ""))
+			}
+
+			nf := DetectColumnFormat(rows, 1)
+			if nf == nil || nf.Type != tt.typ || nf.Pattern != tt.pattern {
+				t.Errorf("got %+v, want %s %q", nf, tt.typ, tt.pattern)
+			}
+		})
+	}
+}
+
+func TestDetectColumnFormatEmpty(t *testing.T) {
+	if nf := DetectColumnFormat([]*sheets.RowData{textRow("a")}, 1); nf != nil {
+		t.Errorf("got %+v, want nil", nf)
+	}
+}
+
+func TestApplyNumberFormat(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	gr := &sheets.GridRange{SheetId: 1, StartRowIndex: 1, StartColumnIndex: 3, EndColumnIndex: 4}
+	nf := &sheets.NumberFormat{Pattern: "0.00%", Type: "PERCENT"}
+	if err := ApplyNumberFormat("ssid", gr, nf, srv); err != nil {
+		t.Fatalf("ApplyNumberFormat: %v", err)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 1 || requests[0].RepeatCell == nil {
+		t.Fatalf("requests = %+v, want a single RepeatCell", requests)
+	}
+
+	req := requests[0].RepeatCell
+	if req.Fields != "userEnteredFormat.numberFormat" {
+		t.Errorf("fields = %q", req.Fields)
+	}
+	if got := req.Cell.UserEnteredFormat.NumberFormat; got.Type != "PERCENT" || got.Pattern != "0.00%" {
+		t.Errorf("number format = %+v", got)
+	}
+	if r := req.Range; r.SheetId != 1 || r.StartRowIndex != 1 || r.StartColumnIndex != 3 || r.EndColumnIndex != 4 {
+		t.Errorf("range = %+v", r)
+	}
+}
@@ -0,0 +1,80 @@
+package rwsheets
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// MaxFormulaLength is the longest formula, in characters, that Google Sheets accepts.
+const MaxFormulaLength = 50000
+
+var (
+	ErrFormulaTooLong = errors.New("formula is longer than the 50,000 characters Sheets allows")
+)
+
+// CheckFormula: Returns ErrFormulaTooLong if the formula is too long for Sheets to accept.
+func CheckFormula(formula string) error {
+	if utf8.RuneCountInString(formula) > MaxFormulaLength {
+		return ErrFormulaTooLong
+	}
+	return nil
+}
+
+// SplitSumFormula: Splits a sum of the given terms into as few "=SUM(...)" formulas as possible,
+// each no longer than maxLen characters. Write each formula to a helper cell and sum the helper
+// cells to get the total. A maxLen of 0 or less uses MaxFormulaLength.
+// Returns ErrFormulaTooLong if a single term can't fit in a formula on its own.
+func SplitSumFormula(terms []string, maxLen int) ([]string, error) {
+	if maxLen <= 0 {
+		maxLen = MaxFormulaLength
+	}
+
+	const prefix, suffix = "=SUM(", ")"
+	var formulas []string
+	var chunk []string
+	length := len(prefix) + len(suffix)
+	for _, term := range terms {
+		termLen := utf8.RuneCountInString(term)
+		if len(prefix)+len(suffix)+termLen > maxLen {
+			return nil, ErrFormulaTooLong
+		}
+
+		// Account for the comma separating this term from the previous one.
+		added := termLen
+		if len(chunk) > 0 {
+			added++
+		}
+		if length+added > maxLen {
+			formulas = append(formulas, prefix+strings.Join(chunk, ",")+suffix)
+			chunk = nil
+			length = len(prefix) + len(suffix)
+			added = termLen
+		}
+
+		chunk = append(chunk, term)
+		length += added
+	}
+	if len(chunk) > 0 {
+		formulas = append(formulas, prefix+strings.Join(chunk, ",")+suffix)
+	}
+
+	return formulas, nil
+}
+
+// FormulaCell: Creates a new sheets formula cell using the stylers settings for the formatting.
+// Returns ErrFormulaTooLong instead of letting the API reject formulas that are too long.
+func (s *Styler) FormulaCell(formula string, borders *BorderConf) (*sheets.CellData, error) {
+	if err := CheckFormula(formula); err != nil {
+		return nil, err
+	}
+
+	format := s.cellFormat(nil, borders)
+
+	return &sheets.CellData{
+		UserEnteredFormat: format,
+		UserEnteredValue:  FormulaValue(formula),
+	}, nil
+}
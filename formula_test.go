@@ -0,0 +1,71 @@
+package rwsheets
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckFormula(t *testing.T) {
+	if err := CheckFormula("=" + strings.Repeat("1", MaxFormulaLength-1)); err != nil {
+		t.Errorf("formula at the limit: %v", err)
+	}
+	if err := CheckFormula("=" + strings.Repeat("1", MaxFormulaLength)); !errors.Is(err, ErrFormulaTooLong) {
+		t.Errorf("oversized formula err = %v, want ErrFormulaTooLong", err)
+	}
+
+	// The limit counts characters, not bytes.
+	if err := CheckFormula("=\"" + strings.Repeat("é", MaxFormulaLength-3) + "\""); err != nil {
+		t.Errorf("multi-byte formula at the limit: %v", err)
+	}
+}
+
+func TestFormulaCell(t *testing.T) {
+	s := NewStyler()
+
+	cell, err := s.FormulaCell("=SUM(A1:A3)", nil)
+	if err != nil {
+		t.Fatalf("FormulaCell: %v", err)
+	}
+	if v := cell.UserEnteredValue; v == nil || v.FormulaValue == nil || *v.FormulaValue != "=SUM(A1:A3)" {
+		t.Errorf("value = %+v", v)
+	}
+
+	if _, err := s.FormulaCell("="+strings.Repeat("1", MaxFormulaLength), nil); !errors.Is(err, ErrFormulaTooLong) {
+		t.Errorf("err = %v, want ErrFormulaTooLong", err)
+	}
+}
+
+func TestSplitSumFormula(t *testing.T) {
+	terms := []string{"A1", "B2", "C3", "D4", "E5"}
+
+	// "=SUM(A1,B2)" is 11 characters, so each formula fits two terms.
+	formulas, err := SplitSumFormula(terms, 11)
+	if err != nil {
+		t.Fatalf("SplitSumFormula: %v", err)
+	}
+	want := []string{"=SUM(A1,B2)", "=SUM(C3,D4)", "=SUM(E5)"}
+	if strings.Join(formulas, " ") != strings.Join(want, " ") {
+		t.Errorf("formulas = %v, want %v", formulas, want)
+	}
+
+	// Everything fits in one formula with the default limit.
+	formulas, err = SplitSumFormula(terms, 0)
+	if err != nil || len(formulas) != 1 || formulas[0] != "=SUM(A1,B2,C3,D4,E5)" {
+		t.Errorf("default limit = %v, %v", formulas, err)
+	}
+
+	for _, formula := range formulas {
+		if err := CheckFormula(formula); err != nil {
+			t.Errorf("%s: %v", formula, err)
+		}
+	}
+
+	if _, err := SplitSumFormula([]string{"A1", "LONGTERM"}, 10); !errors.Is(err, ErrFormulaTooLong) {
+		t.Errorf("oversized term err = %v, want ErrFormulaTooLong", err)
+	}
+
+	if formulas, err := SplitSumFormula(nil, 0); err != nil || len(formulas) != 0 {
+		t.Errorf("no terms = %v, %v", formulas, err)
+	}
+}
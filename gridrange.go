@@ -0,0 +1,51 @@
+package rwsheets
+
+import (
+	"errors"
+	"fmt"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+var (
+	ErrDifferentSheets = errors.New("ranges are on different sheets")
+)
+
+// BoundingRange: Returns the smallest GridRange enclosing all of the ranges, which must be on the same sheet.
+// If any range is unbounded in a direction, so is the result.
+// Returns ErrDifferentSheets if the ranges have different sheet IDs, or ErrInvalidRange if there aren't any.
+func BoundingRange(ranges []*sheets.GridRange) (*sheets.GridRange, error) {
+	var bounds *sheets.GridRange
+	for _, gr := range ranges {
+		if gr == nil {
+			continue
+		}
+		if bounds == nil {
+			copied := *gr
+			bounds = &copied
+			continue
+		}
+
+		if gr.SheetId != bounds.SheetId {
+			return nil, fmt.Errorf("%w: %d and %d", ErrDifferentSheets, bounds.SheetId, gr.SheetId)
+		}
+
+		bounds.StartRowIndex = min(bounds.StartRowIndex, gr.StartRowIndex)
+		bounds.StartColumnIndex = min(bounds.StartColumnIndex, gr.StartColumnIndex)
+		bounds.EndRowIndex = boundingEnd(bounds.EndRowIndex, gr.EndRowIndex)
+		bounds.EndColumnIndex = boundingEnd(bounds.EndColumnIndex, gr.EndColumnIndex)
+	}
+
+	if bounds == nil {
+		return nil, fmt.Errorf("%w: no ranges given", ErrInvalidRange)
+	}
+	return bounds, nil
+}
+
+// boundingEnd: Returns the larger of the two end indices, where an end of 0 is unbounded.
+func boundingEnd(a, b int64) int64 {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return max(a, b)
+}
@@ -0,0 +1,85 @@
+package rwsheets
+
+import (
+	"errors"
+	"testing"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestBoundingRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		ranges []*sheets.GridRange
+		want   string
+	}{
+		{
+			name: "adjacent",
+			ranges: []*sheets.GridRange{
+				{SheetId: 3, StartRowIndex: 1, EndRowIndex: 5, StartColumnIndex: 0, EndColumnIndex: 2},
+				{SheetId: 3, StartRowIndex: 1, EndRowIndex: 5, StartColumnIndex: 2, EndColumnIndex: 4},
+			},
+			want: "A2:D5",
+		},
+		{
+			name: "scattered",
+			ranges: []*sheets.GridRange{
+				{SheetId: 3, StartRowIndex: 9, EndRowIndex: 10, StartColumnIndex: 1, EndColumnIndex: 2},
+				nil,
+				{SheetId: 3, StartRowIndex: 2, EndRowIndex: 3, StartColumnIndex: 4, EndColumnIndex: 6},
+			},
+			want: "B3:F10",
+		},
+		{
+			name: "single",
+			ranges: []*sheets.GridRange{
+				{SheetId: 3, StartRowIndex: 0, EndRowIndex: 1, StartColumnIndex: 0, EndColumnIndex: 1},
+			},
+			want: "A1:A1",
+		},
+		{
+			name: "unbounded rows",
+			ranges: []*sheets.GridRange{
+				{SheetId: 3, StartRowIndex: 4, EndRowIndex: 6, StartColumnIndex: 0, EndColumnIndex: 1},
+				{SheetId: 3, StartColumnIndex: 2, EndColumnIndex: 3},
+			},
+			want: "A:C",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BoundingRange(tt.ranges)
+			if err != nil {
+				t.Fatalf("BoundingRange: %v", err)
+			}
+			if a1 := gridRangeToA1("", got); a1 != tt.want || got.SheetId != 3 {
+				t.Errorf("got %s on sheet %d, want %s on sheet 3", a1, got.SheetId, tt.want)
+			}
+		})
+	}
+}
+
+func TestBoundingRangeDoesNotModifyInput(t *testing.T) {
+	first := &sheets.GridRange{StartRowIndex: 2, EndRowIndex: 3, EndColumnIndex: 1}
+	if _, err := BoundingRange([]*sheets.GridRange{first, {EndRowIndex: 10, EndColumnIndex: 5}}); err != nil {
+		t.Fatalf("BoundingRange: %v", err)
+	}
+	if first.StartRowIndex != 2 || first.EndRowIndex != 3 || first.EndColumnIndex != 1 {
+		t.Errorf("first range was modified: %+v", first)
+	}
+}
+
+func TestBoundingRangeErrors(t *testing.T) {
+	crossSheet := []*sheets.GridRange{
+		{SheetId: 1, EndRowIndex: 1, EndColumnIndex: 1},
+		{SheetId: 2, EndRowIndex: 1, EndColumnIndex: 1},
+	}
+	if _, err := BoundingRange(crossSheet); !errors.Is(err, ErrDifferentSheets) {
+		t.Errorf("cross sheet err = %v, want ErrDifferentSheets", err)
+	}
+
+	if _, err := BoundingRange(nil); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("no ranges err = %v, want ErrInvalidRange", err)
+	}
+}
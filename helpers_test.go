@@ -0,0 +1,152 @@
+package rwsheets
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// fakeCall is a single request received by the fake API.
+type fakeCall struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// fakeHandler returns the status code and the value to encode as the JSON response for the call.
+type fakeHandler func(call fakeCall) (int, interface{})
+
+// fakeAPI serves sheets API requests in tests, recording every call it receives.
+type fakeAPI struct {
+	t       *testing.T
+	handler fakeHandler
+
+	mu    sync.Mutex
+	calls []fakeCall
+}
+
+// newFakeService: Returns a sheets service whose requests are answered by the handler instead of the API.
+// A nil handler answers every call with an empty JSON object.
+func newFakeService(t *testing.T, handler fakeHandler) (*sheets.Service, *fakeAPI) {
+	t.Helper()
+
+	api := &fakeAPI{t: t, handler: handler}
+	client := &http.Client{Transport: api}
+
+	srv, err := NewSheetsServiceWithClient(context.Background(), client)
+	if err != nil {
+		t.Fatalf("NewSheetsServiceWithClient: %v", err)
+	}
+	return srv, api
+}
+
+// RoundTrip: Records the request and answers it using the handler.
+func (a *fakeAPI) RoundTrip(r *http.Request) (*http.Response, error) {
+	var body []byte
+	if r.Body != nil {
+		body, _ = io.ReadAll(r.Body)
+		r.Body.Close()
+	}
+
+	call := fakeCall{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header,
+		Body:   body,
+	}
+
+	a.mu.Lock()
+	a.calls = append(a.calls, call)
+	a.mu.Unlock()
+
+	code, resp := http.StatusOK, interface{}(map[string]interface{}{})
+	if a.handler != nil {
+		code, resp = a.handler(call)
+	}
+
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "application/json")
+	rec.WriteHeader(code)
+	json.NewEncoder(rec).Encode(resp)
+	return rec.Result(), nil
+}
+
+// Calls: Returns every call received so far.
+func (a *fakeAPI) Calls() []fakeCall {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]fakeCall(nil), a.calls...)
+}
+
+// BatchUpdates: Returns the body of every batch update received so far.
+func (a *fakeAPI) BatchUpdates() []*sheets.BatchUpdateSpreadsheetRequest {
+	a.t.Helper()
+
+	var updates []*sheets.BatchUpdateSpreadsheetRequest
+	for _, call := range a.Calls() {
+		if !strings.HasSuffix(call.Path, ":batchUpdate") {
+			continue
+		}
+
+		var update sheets.BatchUpdateSpreadsheetRequest
+		if err := json.Unmarshal(call.Body, &update); err != nil {
+			a.t.Fatalf("decoding batch update: %v", err)
+		}
+		updates = append(updates, &update)
+	}
+	return updates
+}
+
+// Requests: Returns the requests of the only batch update received, failing the test if there wasn't exactly one.
+func (a *fakeAPI) Requests() []*sheets.Request {
+	a.t.Helper()
+
+	updates := a.BatchUpdates()
+	if len(updates) != 1 {
+		a.t.Fatalf("got %d batch updates, want 1", len(updates))
+	}
+	return updates[0].Requests
+}
+
+// apiError: Returns the response body the API sends for an error with the given status code.
+func apiError(code int) interface{} {
+	return map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": http.StatusText(code),
+		},
+	}
+}
+
+// textRow: Returns a row of text cells with the given values.
+func textRow(values ...string) *sheets.RowData {
+	var cells []*sheets.CellData
+	for _, value := range values {
+		cells = append(cells, &sheets.CellData{UserEnteredValue: TextValue(value)})
+	}
+	return &sheets.RowData{Values: cells}
+}
+
+// gridResponse: Returns a spreadsheet holding a single grid of rows starting at the given indices.
+func gridResponse(startRow, startCol int64, rows ...*sheets.RowData) *sheets.Spreadsheet {
+	return &sheets.Spreadsheet{
+		Sheets: []*sheets.Sheet{{
+			Data: []*sheets.GridData{{
+				RowData:     rows,
+				StartColumn: startCol,
+				StartRow:    startRow,
+			}},
+		}},
+	}
+}
@@ -0,0 +1,104 @@
+package rwsheets
+
+import (
+	"errors"
+	"fmt"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+var (
+	ErrInvalidDimension = errors.New("dimension must be either ROWS or COLUMNS")
+)
+
+// validDimension: Checks the dimension is either ROWS or COLUMNS.
+func validDimension(dimension string) error {
+	if dimension != "ROWS" && dimension != "COLUMNS" {
+		return ErrInvalidDimension
+	}
+	return nil
+}
+
+// DeleteRange: Deletes the cells in the range, shifting the remaining cells to fill the gap.
+// shiftDimension should be "ROWS" to shift cells up or "COLUMNS" to shift cells left.
+func DeleteRange(ssid string, gr *sheets.GridRange, shiftDimension string, srv *sheets.Service) error {
+	if err := validDimension(shiftDimension); err != nil {
+		return err
+	}
+
+	request := sheets.Request{
+		DeleteRange: &sheets.DeleteRangeRequest{
+			Range:          gr,
+			ShiftDimension: shiftDimension,
+		},
+	}
+
+	_, err := batchUpdate(ssid, []*sheets.Request{&request}, srv)
+	return err
+}
+
+// InsertRange: Inserts empty cells into the range, shifting the existing cells out of the way.
+// shiftDimension should be "ROWS" to shift cells down or "COLUMNS" to shift cells right.
+func InsertRange(ssid string, gr *sheets.GridRange, shiftDimension string, srv *sheets.Service) error {
+	if err := validDimension(shiftDimension); err != nil {
+		return err
+	}
+
+	request := sheets.Request{
+		InsertRange: &sheets.InsertRangeRequest{
+			Range:          gr,
+			ShiftDimension: shiftDimension,
+		},
+	}
+
+	_, err := batchUpdate(ssid, []*sheets.Request{&request}, srv)
+	return err
+}
+
+// DeleteRows: Deletes the rows of the sheet from the zero-based startIndex up to, but not including, endIndex.
+// The rows are removed from the sheet and everything below them shifts up.
+func DeleteRows(ssid string, gid, startIndex, endIndex int64, srv *sheets.Service) error {
+	if startIndex < 0 || startIndex >= endIndex {
+		return fmt.Errorf("%w: start row %d must be before end row %d", ErrInvalidRange, startIndex, endIndex)
+	}
+
+	request := sheets.Request{
+		DeleteDimension: &sheets.DeleteDimensionRequest{
+			Range: &sheets.DimensionRange{
+				Dimension:  "ROWS",
+				EndIndex:   endIndex,
+				SheetId:    gid,
+				StartIndex: startIndex,
+			},
+		},
+	}
+
+	_, err := batchUpdate(ssid, []*sheets.Request{&request}, srv)
+	return err
+}
+
+// InsertRows: Inserts count blank rows into the sheet before the zero-based startIndex, shifting the existing rows down.
+// If inheritFromBefore is true the new rows take their formatting from the row above them,
+// otherwise from the row below. Rows inserted at the top of the sheet can only inherit from below.
+func InsertRows(ssid string, gid, startIndex, count int64, inheritFromBefore bool, srv *sheets.Service) error {
+	if startIndex < 0 || count <= 0 {
+		return fmt.Errorf("%w: inserting %d rows at row %d", ErrInvalidRange, count, startIndex)
+	}
+
+	request := sheets.Request{
+		InsertDimension: &sheets.InsertDimensionRequest{
+			InheritFromBefore: inheritFromBefore,
+			Range: &sheets.DimensionRange{
+				Dimension:  "ROWS",
+				EndIndex:   startIndex + count,
+				SheetId:    gid,
+				StartIndex: startIndex,
+			},
+			// InheritFromBefore would otherwise be dropped from the request when false.
+			ForceSendFields: []string{"InheritFromBefore"},
+		},
+	}
+
+	_, err := batchUpdate(ssid, []*sheets.Request{&request}, srv)
+	return err
+}
@@ -0,0 +1,137 @@
+package rwsheets
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestDeleteRange(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	gr := &sheets.GridRange{SheetId: 2, StartRowIndex: 3, EndRowIndex: 6, StartColumnIndex: 1, EndColumnIndex: 4}
+	if err := DeleteRange("ssid", gr, "COLUMNS", srv); err != nil {
+		t.Fatalf("DeleteRange: %v", err)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 1 || requests[0].DeleteRange == nil {
+		t.Fatalf("requests = %+v, want a single DeleteRange", requests)
+	}
+
+	req := requests[0].DeleteRange
+	if req.ShiftDimension != "COLUMNS" {
+		t.Errorf("shift dimension = %q, want COLUMNS", req.ShiftDimension)
+	}
+	if r := req.Range; r.SheetId != 2 || r.StartRowIndex != 3 || r.EndRowIndex != 6 || r.StartColumnIndex != 1 || r.EndColumnIndex != 4 {
+		t.Errorf("range = %+v", r)
+	}
+}
+
+func TestDeleteRangeInvalidDimension(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	if err := DeleteRange("ssid", &sheets.GridRange{}, "UP", srv); !errors.Is(err, ErrInvalidDimension) {
+		t.Errorf("err = %v, want ErrInvalidDimension", err)
+	}
+	if calls := api.Calls(); len(calls) != 0 {
+		t.Errorf("got %d calls, want none", len(calls))
+	}
+}
+
+func TestInsertRange(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	gr := &sheets.GridRange{SheetId: 2, StartRowIndex: 1, EndRowIndex: 3, StartColumnIndex: 0, EndColumnIndex: 5}
+	if err := InsertRange("ssid", gr, "ROWS", srv); err != nil {
+		t.Fatalf("InsertRange: %v", err)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 1 || requests[0].InsertRange == nil {
+		t.Fatalf("requests = %+v, want a single InsertRange", requests)
+	}
+
+	req := requests[0].InsertRange
+	if req.ShiftDimension != "ROWS" {
+		t.Errorf("shift dimension = %q, want ROWS", req.ShiftDimension)
+	}
+	if r := req.Range; r.SheetId != 2 || r.StartRowIndex != 1 || r.EndRowIndex != 3 || r.EndColumnIndex != 5 {
+		t.Errorf("range = %+v", r)
+	}
+
+	if err := InsertRange("ssid", gr, "rows", srv); !errors.Is(err, ErrInvalidDimension) {
+		t.Errorf("err = %v, want ErrInvalidDimension", err)
+	}
+}
+
+func TestDeleteRows(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	if err := DeleteRows("ssid", 4, 2, 5, srv); err != nil {
+		t.Fatalf("DeleteRows: %v", err)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 1 || requests[0].DeleteDimension == nil {
+		t.Fatalf("requests = %+v, want a single DeleteDimension", requests)
+	}
+	if r := requests[0].DeleteDimension.Range; r.Dimension != "ROWS" || r.SheetId != 4 || r.StartIndex != 2 || r.EndIndex != 5 {
+		t.Errorf("range = %+v, want rows 2 to 5 of sheet 4", r)
+	}
+}
+
+func TestDeleteRowsInvalid(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	for _, indices := range [][2]int64{{3, 3}, {5, 2}, {-1, 2}} {
+		if err := DeleteRows("ssid", 0, indices[0], indices[1], srv); !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("DeleteRows(%d, %d) = %v, want ErrInvalidRange", indices[0], indices[1], err)
+		}
+	}
+	if calls := api.Calls(); len(calls) != 0 {
+		t.Errorf("got %d calls, want none", len(calls))
+	}
+}
+
+func TestInsertRows(t *testing.T) {
+	for _, inherit := range []bool{true, false} {
+		srv, api := newFakeService(t, nil)
+
+		if err := InsertRows("ssid", 3, 10, 2, inherit, srv); err != nil {
+			t.Fatalf("InsertRows: %v", err)
+		}
+
+		requests := api.Requests()
+		if len(requests) != 1 || requests[0].InsertDimension == nil {
+			t.Fatalf("requests = %+v, want a single InsertDimension", requests)
+		}
+
+		req := requests[0].InsertDimension
+		if req.InheritFromBefore != inherit {
+			t.Errorf("inherit from before = %v, want %v", req.InheritFromBefore, inherit)
+		}
+		if r := req.Range; r.Dimension != "ROWS" || r.SheetId != 3 || r.StartIndex != 10 || r.EndIndex != 12 {
+			t.Errorf("range = %+v, want rows 10 to 12 of sheet 3", r)
+		}
+		if body := string(api.Calls()[0].Body); !strings.Contains(body, `"inheritFromBefore":`) {
+			t.Errorf("body %s is missing inheritFromBefore", body)
+		}
+	}
+}
+
+func TestInsertRowsInvalid(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	if err := InsertRows("ssid", 0, 0, 0, false, srv); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("no rows err = %v, want ErrInvalidRange", err)
+	}
+	if err := InsertRows("ssid", 0, -1, 2, false, srv); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("negative index err = %v, want ErrInvalidRange", err)
+	}
+	if calls := api.Calls(); len(calls) != 0 {
+		t.Errorf("got %d calls, want none", len(calls))
+	}
+}
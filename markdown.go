@@ -0,0 +1,98 @@
+package rwsheets
+
+import (
+	"strings"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// markdownToken: A piece of markdown text, either plain text or a "**" or "*" marker.
+type markdownToken struct {
+	text   string
+	marker bool
+}
+
+// tokenizeMarkdown: Splits the markdown into plain text and bold or italic markers.
+// A marker without a matching closing marker is kept as plain text.
+func tokenizeMarkdown(md string) []markdownToken {
+	var tokens []markdownToken
+	counts := make(map[string]int)
+
+	for len(md) > 0 {
+		i := strings.IndexByte(md, '*')
+		if i < 0 {
+			tokens = append(tokens, markdownToken{text: md})
+			break
+		}
+		if i > 0 {
+			tokens = append(tokens, markdownToken{text: md[:i]})
+		}
+
+		marker := "*"
+		if strings.HasPrefix(md[i:], "**") {
+			marker = "**"
+		}
+		tokens = append(tokens, markdownToken{text: marker, marker: true})
+		counts[marker]++
+		md = md[i+len(marker):]
+	}
+
+	// An odd number of a marker means the last one is never closed.
+	for i := len(tokens) - 1; i >= 0; i-- {
+		if tokens[i].marker && counts[tokens[i].text]%2 == 1 {
+			counts[tokens[i].text]--
+			tokens[i].marker = false
+		}
+	}
+	return tokens
+}
+
+// MarkdownCell: Creates a new sheets text cell from a small subset of markdown using the stylers settings for the formatting.
+// Text wrapped in "**" is bold and text wrapped in "*" is italic. The markers are removed from the cell's text.
+//
+// Bold and italic are toggled independently, so nested markers like "**bold *both***" and overlapping markers
+// like "**a *b** c*" both work. A marker without a closing marker is left in the text as is,
+// and escaping markers with a backslash is not supported.
+func (s *Styler) MarkdownCell(md string, borders *BorderConf) *sheets.CellData {
+	var (
+		text   strings.Builder
+		length int64
+		runs   []*sheets.TextFormatRun
+		bold   bool
+		italic bool
+	)
+
+	// A run is only started when the text after a marker differs in style from the run before it.
+	lastBold, lastItalic := false, false
+	for _, token := range tokenizeMarkdown(md) {
+		if token.marker {
+			if token.text == "**" {
+				bold = !bold
+			} else {
+				italic = !italic
+			}
+			continue
+		}
+
+		if len(runs) == 0 || bold != lastBold || italic != lastItalic {
+			runs = append(runs, &sheets.TextFormatRun{
+				Format: &sheets.TextFormat{
+					Bold:            bold,
+					Italic:          italic,
+					ForceSendFields: []string{"Bold", "Italic"},
+				},
+				StartIndex: length,
+			})
+			lastBold, lastItalic = bold, italic
+		}
+
+		text.WriteString(token.text)
+		length += textLength(token.text)
+	}
+
+	return &sheets.CellData{
+		TextFormatRuns:    runs,
+		UserEnteredFormat: s.cellFormat(nil, borders),
+		UserEnteredValue:  TextValue(text.String()),
+	}
+}
@@ -0,0 +1,68 @@
+package rwsheets
+
+import "testing"
+
+// runStyle is the expected start index and style of a text format run.
+type runStyle struct {
+	start        int64
+	bold, italic bool
+}
+
+func TestMarkdownCell(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+		text string
+		runs []runStyle
+	}{
+		{
+			name: "bold",
+			md:   "a **b** c",
+			text: "a b c",
+			runs: []runStyle{{0, false, false}, {2, true, false}, {3, false, false}},
+		},
+		{
+			name: "italic",
+			md:   "*a* b",
+			text: "a b",
+			runs: []runStyle{{0, false, true}, {1, false, false}},
+		},
+		{
+			name: "mixed",
+			md:   "**bold *both*** *ital*",
+			text: "bold both ital",
+			runs: []runStyle{{0, true, false}, {5, true, true}, {9, false, false}, {10, false, true}},
+		},
+		{
+			name: "unclosed marker",
+			md:   "2 * 3 is **6**",
+			text: "2 * 3 is 6",
+			runs: []runStyle{{0, false, false}, {9, true, false}},
+		},
+		{
+			name: "utf-16 offsets",
+			md:   "😀 **x**",
+			text: "😀 x",
+			runs: []runStyle{{0, false, false}, {3, true, false}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cell := NewStyler().MarkdownCell(tt.md, nil)
+
+			if got := *cell.UserEnteredValue.StringValue; got != tt.text {
+				t.Errorf("text = %q, want %q", got, tt.text)
+			}
+			if len(cell.TextFormatRuns) != len(tt.runs) {
+				t.Fatalf("got %d runs, want %d", len(cell.TextFormatRuns), len(tt.runs))
+			}
+			for i, run := range cell.TextFormatRuns {
+				got := runStyle{run.StartIndex, run.Format.Bold, run.Format.Italic}
+				if got != tt.runs[i] {
+					t.Errorf("run %d = %+v, want %+v", i, got, tt.runs[i])
+				}
+			}
+		})
+	}
+}
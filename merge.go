@@ -0,0 +1,108 @@
+package rwsheets
+
+import (
+	"errors"
+	"fmt"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+var (
+	ErrInvalidMergeType = errors.New("merge type must be one of MERGE_ALL, MERGE_COLUMNS or MERGE_ROWS")
+)
+
+// MergeCells: Merges the cells from the zero-based startRow and startCol up to, but not including, endRow and endCol.
+// mergeType should be "MERGE_ALL" to merge the whole range into one cell, "MERGE_COLUMNS" to merge each column
+// or "MERGE_ROWS" to merge each row. Only the value of the top left cell of each merge is kept.
+func MergeCells(ssid string, gid, startRow, endRow, startCol, endCol int64, mergeType string, srv *sheets.Service) error {
+	switch mergeType {
+	case "MERGE_ALL", "MERGE_COLUMNS", "MERGE_ROWS":
+	default:
+		return fmt.Errorf("%w, got %q", ErrInvalidMergeType, mergeType)
+	}
+
+	gr, err := mergeRange(gid, startRow, endRow, startCol, endCol)
+	if err != nil {
+		return err
+	}
+
+	request := sheets.Request{
+		MergeCells: &sheets.MergeCellsRequest{
+			MergeType: mergeType,
+			Range:     gr,
+		},
+	}
+
+	_, err = batchUpdate(ssid, []*sheets.Request{&request}, srv)
+	return err
+}
+
+// UnmergeCells: Unmerges every merged cell within the cells from the zero-based startRow and startCol
+// up to, but not including, endRow and endCol. Merges only partly inside the range can't be unmerged,
+// use CheckMergeConflicts to find their full ranges.
+func UnmergeCells(ssid string, gid, startRow, endRow, startCol, endCol int64, srv *sheets.Service) error {
+	gr, err := mergeRange(gid, startRow, endRow, startCol, endCol)
+	if err != nil {
+		return err
+	}
+
+	request := sheets.Request{
+		UnmergeCells: &sheets.UnmergeCellsRequest{
+			Range: gr,
+		},
+	}
+
+	_, err = batchUpdate(ssid, []*sheets.Request{&request}, srv)
+	return err
+}
+
+// mergeRange: Returns the GridRange for the cells, or ErrInvalidRange if they don't cover at least one cell.
+func mergeRange(gid, startRow, endRow, startCol, endCol int64) (*sheets.GridRange, error) {
+	if startRow < 0 || startCol < 0 || startRow >= endRow || startCol >= endCol {
+		return nil, fmt.Errorf("%w: rows %d to %d, columns %d to %d", ErrInvalidRange, startRow, endRow, startCol, endCol)
+	}
+
+	return &sheets.GridRange{
+		EndColumnIndex:   endCol,
+		EndRowIndex:      endRow,
+		SheetId:          gid,
+		StartColumnIndex: startCol,
+		StartRowIndex:    startRow,
+	}, nil
+}
+
+// CheckMergeConflicts: Returns the merged ranges on the sheet of the GridRange that intersect it,
+// so they can be unmerged before writing. Writing into part of a merged range fails,
+// so this turns that failure into a check that can be made up front.
+// Unbounded end indices on the GridRange extend to the end of the sheet.
+func CheckMergeConflicts(ssid string, gr *sheets.GridRange, srv *sheets.Service) ([]*sheets.GridRange, error) {
+	ss, err := srv.Spreadsheets.Get(ssid).Fields("sheets(properties.sheetId,merges)").Do()
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []*sheets.GridRange
+	for _, sheet := range ss.Sheets {
+		if sheet.Properties == nil || sheet.Properties.SheetId != gr.SheetId {
+			continue
+		}
+
+		for _, merge := range sheet.Merges {
+			if rangesIntersect(gr, merge) {
+				conflicts = append(conflicts, merge)
+			}
+		}
+	}
+	return conflicts, nil
+}
+
+// rangesIntersect: Returns true if the two ranges share at least one cell, ignoring their sheet IDs.
+func rangesIntersect(a, b *sheets.GridRange) bool {
+	return spansIntersect(a.StartRowIndex, a.EndRowIndex, b.StartRowIndex, b.EndRowIndex) &&
+		spansIntersect(a.StartColumnIndex, a.EndColumnIndex, b.StartColumnIndex, b.EndColumnIndex)
+}
+
+// spansIntersect: Returns true if the half open spans overlap, treating an end of 0 as unbounded.
+func spansIntersect(startA, endA, startB, endB int64) bool {
+	return (endB == 0 || startA < endB) && (endA == 0 || startB < endA)
+}
@@ -0,0 +1,129 @@
+package rwsheets
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestCheckMergeConflicts(t *testing.T) {
+	// B2:C3 overlaps the target, E1:F1 is beside it and the merge on sheet 2 is on another sheet.
+	overlapping := &sheets.GridRange{SheetId: 1, StartRowIndex: 1, EndRowIndex: 3, StartColumnIndex: 1, EndColumnIndex: 3}
+	beside := &sheets.GridRange{SheetId: 1, StartRowIndex: 0, EndRowIndex: 1, StartColumnIndex: 4, EndColumnIndex: 6}
+	other := &sheets.GridRange{SheetId: 2, StartRowIndex: 0, EndRowIndex: 5, StartColumnIndex: 0, EndColumnIndex: 5}
+
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusOK, &sheets.Spreadsheet{
+			Sheets: []*sheets.Sheet{
+				{Properties: &sheets.SheetProperties{SheetId: 1}, Merges: []*sheets.GridRange{overlapping, beside}},
+				{Properties: &sheets.SheetProperties{SheetId: 2}, Merges: []*sheets.GridRange{other}},
+			},
+		}
+	})
+
+	// A1:D2 on sheet 1.
+	target := &sheets.GridRange{SheetId: 1, StartRowIndex: 0, EndRowIndex: 2, StartColumnIndex: 0, EndColumnIndex: 4}
+	conflicts, err := CheckMergeConflicts("ssid", target, srv)
+	if err != nil {
+		t.Fatalf("CheckMergeConflicts: %v", err)
+	}
+	if len(conflicts) != 1 || gridRangeToA1("", conflicts[0]) != "B2:C3" {
+		t.Errorf("conflicts = %+v, want B2:C3", conflicts)
+	}
+
+	if got := api.Calls()[0].Query.Get("fields"); got != "sheets(properties.sheetId,merges)" {
+		t.Errorf("fields = %q", got)
+	}
+
+	// Unbounded columns reach the merge beside the target.
+	target = &sheets.GridRange{SheetId: 1, StartRowIndex: 0, EndRowIndex: 1, StartColumnIndex: 3}
+	conflicts, err = CheckMergeConflicts("ssid", target, srv)
+	if err != nil {
+		t.Fatalf("CheckMergeConflicts: %v", err)
+	}
+	if len(conflicts) != 1 || gridRangeToA1("", conflicts[0]) != "E1:F1" {
+		t.Errorf("conflicts = %+v, want E1:F1", conflicts)
+	}
+}
+
+func TestRangesIntersect(t *testing.T) {
+	a1c3 := &sheets.GridRange{EndRowIndex: 3, EndColumnIndex: 1}
+	tests := []struct {
+		name string
+		b    *sheets.GridRange
+		want bool
+	}{
+		{"same", &sheets.GridRange{EndRowIndex: 3, EndColumnIndex: 1}, true},
+		{"below", &sheets.GridRange{StartRowIndex: 3, EndRowIndex: 4, EndColumnIndex: 1}, false},
+		{"beside", &sheets.GridRange{EndRowIndex: 3, StartColumnIndex: 1, EndColumnIndex: 2}, false},
+		{"corner", &sheets.GridRange{StartRowIndex: 2, EndRowIndex: 5, StartColumnIndex: 0, EndColumnIndex: 2}, true},
+		{"whole sheet", &sheets.GridRange{}, true},
+	}
+
+	for _, tt := range tests {
+		if got := rangesIntersect(a1c3, tt.b); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+		if got := rangesIntersect(tt.b, a1c3); got != tt.want {
+			t.Errorf("%s reversed: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMergeCells(t *testing.T) {
+	for _, mergeType := range []string{"MERGE_ALL", "MERGE_COLUMNS", "MERGE_ROWS"} {
+		srv, api := newFakeService(t, nil)
+
+		if err := MergeCells("ssid", 2, 0, 1, 0, 5, mergeType, srv); err != nil {
+			t.Fatalf("MergeCells(%s): %v", mergeType, err)
+		}
+
+		requests := api.Requests()
+		if len(requests) != 1 || requests[0].MergeCells == nil {
+			t.Fatalf("requests = %+v, want a single MergeCells", requests)
+		}
+
+		req := requests[0].MergeCells
+		if req.MergeType != mergeType {
+			t.Errorf("merge type = %q, want %q", req.MergeType, mergeType)
+		}
+		if got := gridRangeToA1("", req.Range); got != "A1:E1" || req.Range.SheetId != 2 {
+			t.Errorf("range = %s on sheet %d, want A1:E1 on sheet 2", got, req.Range.SheetId)
+		}
+	}
+}
+
+func TestMergeCellsInvalid(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	if err := MergeCells("ssid", 0, 0, 1, 0, 5, "merge_all", srv); !errors.Is(err, ErrInvalidMergeType) {
+		t.Errorf("err = %v, want ErrInvalidMergeType", err)
+	}
+	if err := MergeCells("ssid", 0, 1, 1, 0, 5, "MERGE_ALL", srv); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("empty range err = %v, want ErrInvalidRange", err)
+	}
+	if err := UnmergeCells("ssid", 0, 0, 1, 3, 2, srv); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("unmerge err = %v, want ErrInvalidRange", err)
+	}
+	if len(api.Calls()) != 0 {
+		t.Error("invalid merges called the API")
+	}
+}
+
+func TestUnmergeCells(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	if err := UnmergeCells("ssid", 2, 0, 3, 1, 4, srv); err != nil {
+		t.Fatalf("UnmergeCells: %v", err)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 1 || requests[0].UnmergeCells == nil {
+		t.Fatalf("requests = %+v, want a single UnmergeCells", requests)
+	}
+	if r := requests[0].UnmergeCells.Range; gridRangeToA1("", r) != "B1:D3" || r.SheetId != 2 {
+		t.Errorf("range = %+v, want B1:D3 on sheet 2", r)
+	}
+}
@@ -0,0 +1,61 @@
+package rwsheets
+
+import (
+	"context"
+	"sync"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// UpdateJob: struct holding the parameters of a single UpdateSheetData call, to be used with UpdateManyParallel.
+type UpdateJob struct {
+	SSID             string
+	EndColumnIndex   int64
+	GID              int64
+	StartColumnIndex int64
+	StartRowIndex    int64
+	Rows             []*sheets.RowData
+}
+
+// UpdateManyParallel: Runs the update jobs with at most concurrency of them at a time, returning the error
+// of each job at the same index as the job. Every job waits on the shared limiter before calling the API,
+// so the writes stay within the quota no matter the concurrency. *rate.Limiter from golang.org/x/time/rate
+// can be used as the limiter, and a nil limiter doesn't limit the rate.
+//
+// A failed job doesn't stop the others. Once the context is cancelled, jobs that haven't started
+// are skipped and get the contexts error. Concurrency below 1 is treated as 1.
+func UpdateManyParallel(ctx context.Context, jobs []UpdateJob, concurrency int, limiter RateLimiter, srv *sheets.Service) []error {
+	errs := make([]error, len(jobs))
+
+	opts := []ClientOption{WithContext(ctx)}
+	if limiter != nil {
+		opts = append(opts, WithRateLimiter(limiter))
+	}
+	client := NewClient(srv, opts...)
+
+	sem := make(chan struct{}, max(concurrency, 1))
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		if err := ctx.Err(); err != nil {
+			errs[i] = err
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(i int, job UpdateJob) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			errs[i] = client.Update(job.SSID, job.EndColumnIndex, job.GID, job.StartColumnIndex, job.StartRowIndex, job.Rows)
+		}(i, job)
+	}
+	wg.Wait()
+
+	return errs
+}
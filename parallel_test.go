@@ -0,0 +1,121 @@
+package rwsheets
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// recordingLimiter records the order of the waits made on it, failing any made after the API was called
+// without a wait of its own.
+type recordingLimiter struct {
+	mu      sync.Mutex
+	waits   int
+	pending int // Waits not yet followed by a call.
+}
+
+func (l *recordingLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.waits++
+	l.pending++
+	return nil
+}
+
+// call: Records a call to the API, reporting whether it was preceded by a wait.
+func (l *recordingLimiter) call() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.pending == 0 {
+		return false
+	}
+	l.pending--
+	return true
+}
+
+func TestUpdateManyParallel(t *testing.T) {
+	limiter := &recordingLimiter{}
+	var inFlight, maxInFlight int32
+
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		if !limiter.call() {
+			t.Error("API called without waiting on the limiter")
+		}
+
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		if strings.Contains(call.Path, "/broken:") {
+			return http.StatusBadRequest, apiError(http.StatusBadRequest)
+		}
+		return http.StatusOK, map[string]interface{}{}
+	})
+
+	var jobs []UpdateJob
+	for _, ssid := range []string{"one", "two", "broken", "three", "four", "five"} {
+		jobs = append(jobs, UpdateJob{SSID: ssid, EndColumnIndex: 1, GID: 2, StartRowIndex: 3, Rows: []*sheets.RowData{textRow(ssid)}})
+	}
+
+	errs := UpdateManyParallel(context.Background(), jobs, 2, limiter, srv)
+	if len(errs) != len(jobs) {
+		t.Fatalf("got %d errors, want one per job", len(errs))
+	}
+	for i, err := range errs {
+		if jobs[i].SSID == "broken" {
+			if err == nil {
+				t.Error("the broken job succeeded")
+			}
+		} else if err != nil {
+			t.Errorf("job %s: %v", jobs[i].SSID, err)
+		}
+	}
+
+	if limiter.waits != len(jobs) {
+		t.Errorf("limiter waited %d times, want %d", limiter.waits, len(jobs))
+	}
+	if n := atomic.LoadInt32(&maxInFlight); n > 2 {
+		t.Errorf("%d jobs ran at once, want at most 2", n)
+	}
+
+	updates := api.BatchUpdates()
+	if len(updates) != len(jobs) {
+		t.Fatalf("got %d batch updates, want %d", len(updates), len(jobs))
+	}
+	for _, update := range updates {
+		uc := update.Requests[0].UpdateCells
+		if uc == nil || uc.Range.SheetId != 2 || uc.Range.StartRowIndex != 3 || uc.Range.EndRowIndex != 4 {
+			t.Errorf("request = %+v, want the jobs update", update.Requests[0])
+		}
+	}
+}
+
+func TestUpdateManyParallelCancelled(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	jobs := []UpdateJob{{SSID: "one"}, {SSID: "two"}}
+	for i, err := range UpdateManyParallel(ctx, jobs, 0, nil, srv) {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("job %d err = %v, want context.Canceled", i, err)
+		}
+	}
+	if calls := api.Calls(); len(calls) != 0 {
+		t.Errorf("got %d calls, want none", len(calls))
+	}
+}
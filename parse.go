@@ -0,0 +1,219 @@
+package rwsheets
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// ParseFormattedValue: Converts a FORMATTED_VALUE string back into a typed Go value.
+//
+// The number format type decides how the value is parsed:
+// CURRENCY, NUMBER and SCIENTIFIC values are returned as a float64,
+// PERCENT values are returned as a float64 divided by 100,
+// DATE, TIME and DATE_TIME values are returned as a time.Time parsed using the format's pattern,
+// and anything else is returned as the original string.
+func ParseFormattedValue(s string, numberFormat *sheets.NumberFormat) (interface{}, error) {
+	if numberFormat == nil {
+		return s, nil
+	}
+
+	switch numberFormat.Type {
+	case "CURRENCY", "NUMBER", "SCIENTIFIC":
+		return parseFormattedNumber(s)
+	case "PERCENT":
+		value, err := parseFormattedNumber(strings.TrimSuffix(strings.TrimSpace(s), "%"))
+		if err != nil {
+			return nil, err
+		}
+		return value / 100, nil
+	case "DATE", "TIME", "DATE_TIME":
+		return parseFormattedDate(s, numberFormat)
+	}
+
+	return s, nil
+}
+
+// groupedNumberPattern matches the integer part of a number grouped by thousands, like "-1,234,567".
+var groupedNumberPattern = regexp.MustCompile(`^[-+]?\d{1,3}(,\d{3})+$`)
+
+// parseFormattedNumber: Strips currency symbols, spaces, grouping commas and accounting parentheses before parsing the number.
+// Anything else, like letters or a comma used as the decimal separator, is an error rather than being dropped.
+func parseFormattedNumber(s string) (float64, error) {
+	value := strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Sc, r) || unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+
+	// Accounting formats show zero as a dash, like "$ -".
+	if value == "-" {
+		return float64(0.0), nil
+	}
+
+	// Accounting formats put the currency symbol outside the parentheses, like "$ (1,234.50)".
+	negative := strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")")
+	if negative {
+		value = value[1 : len(value)-1]
+	}
+
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9', r == '.', r == ',', r == '-', r == '+', r == 'E', r == 'e':
+		default:
+			return float64(0.0), fmt.Errorf("unable to parse %q as a number: unexpected %q", s, r)
+		}
+	}
+
+	// Commas are only allowed as thousands separators in the integer part.
+	if strings.Contains(value, ",") {
+		integer := value
+		if i := strings.IndexAny(value, ".eE"); i >= 0 {
+			integer = value[:i]
+		}
+		if strings.Contains(value[len(integer):], ",") || !groupedNumberPattern.MatchString(integer) {
+			return float64(0.0), fmt.Errorf("unable to parse %q as a number: misplaced grouping separator", s)
+		}
+		value = strings.ReplaceAll(value, ",", "")
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return float64(0.0), fmt.Errorf("unable to parse %q as a number: %w", s, err)
+	}
+
+	if negative {
+		number = -number
+	}
+	return number, nil
+}
+
+// parseFormattedDate: Parses a date, time or date time string using the number formats pattern.
+func parseFormattedDate(s string, numberFormat *sheets.NumberFormat) (time.Time, error) {
+	var layouts []string
+	if numberFormat.Pattern != "" {
+		layouts = append(layouts, DateLayout(numberFormat.Pattern))
+	}
+
+	// Fall back to the layouts Sheets uses for its default formats.
+	switch numberFormat.Type {
+	case "DATE":
+		layouts = append(layouts, "1/2/2006", "2006-01-02")
+	case "TIME":
+		layouts = append(layouts, "3:04:05 PM", "15:04:05")
+	case "DATE_TIME":
+		layouts = append(layouts, "1/2/2006 15:04:05", "1/2/2006 3:04:05 PM", "2006-01-02 15:04:05")
+	}
+
+	var err error
+	for _, layout := range layouts {
+		var t time.Time
+		if t, err = time.Parse(layout, strings.TrimSpace(s)); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unable to parse %q as a date: %w", s, err)
+}
+
+// DateLayout: Converts a Sheets date/time pattern, like "M/d/yyyy", into a Go time layout.
+// Lowercase "m" is treated as minutes when it follows an hour token or precedes a seconds token,
+// and as a month otherwise, matching how Sheets interprets it.
+func DateLayout(pattern string) string {
+	var tokens []string
+	for i := 0; i < len(pattern); {
+		if strings.HasPrefix(strings.ToLower(pattern[i:]), "am/pm") {
+			tokens = append(tokens, "am/pm")
+			i += len("am/pm")
+			continue
+		}
+
+		j := i + 1
+		for j < len(pattern) && pattern[j] == pattern[i] {
+			j++
+		}
+		tokens = append(tokens, pattern[i:j])
+		i = j
+	}
+
+	var layout strings.Builder
+	lastWasHour := false
+	for i, token := range tokens {
+		isHour := false
+		switch token {
+		case "yyyy", "YYYY":
+			layout.WriteString("2006")
+		case "yy", "YY":
+			layout.WriteString("06")
+		case "MMMM", "mmmm":
+			layout.WriteString("January")
+		case "MMM", "mmm":
+			layout.WriteString("Jan")
+		case "dddd":
+			layout.WriteString("Monday")
+		case "ddd":
+			layout.WriteString("Mon")
+		case "dd":
+			layout.WriteString("02")
+		case "d":
+			layout.WriteString("2")
+		case "HH", "H":
+			layout.WriteString("15")
+			isHour = true
+		case "hh":
+			layout.WriteString("03")
+			isHour = true
+		case "h":
+			layout.WriteString("3")
+			isHour = true
+		case "ss":
+			layout.WriteString("05")
+		case "s":
+			layout.WriteString("5")
+		case "MM", "M", "mm", "m":
+			minutes := token[0] == 'm' && (lastWasHour || nextIsSeconds(tokens[i+1:]))
+			switch {
+			case minutes && len(token) == 2:
+				layout.WriteString("04")
+			case minutes:
+				layout.WriteString("4")
+			case len(token) == 2:
+				layout.WriteString("01")
+			default:
+				layout.WriteString("1")
+			}
+		case "am/pm":
+			layout.WriteString("PM")
+		default:
+			layout.WriteString(token)
+		}
+
+		// Separators between the hour and minute tokens shouldn't reset the hour flag.
+		if isHour {
+			lastWasHour = true
+		} else if strings.ContainsAny(token[:1], "yMdsmhH") {
+			lastWasHour = false
+		}
+	}
+
+	return layout.String()
+}
+
+// nextIsSeconds: Reports whether the next date token in the list is a seconds token.
+func nextIsSeconds(tokens []string) bool {
+	for _, token := range tokens {
+		switch token[0] {
+		case 's':
+			return true
+		case 'y', 'Y', 'M', 'd', 'h', 'H', 'm':
+			return false
+		}
+	}
+	return false
+}
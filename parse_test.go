@@ -0,0 +1,108 @@
+package rwsheets
+
+import (
+	"testing"
+	"time"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestParseFormattedValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		format *sheets.NumberFormat
+		want   interface{}
+	}{
+		{"currency", "$1,234.50", &sheets.NumberFormat{Type: "CURRENCY"}, 1234.5},
+		{"accounting negative", "$ (1,234.50)", &sheets.NumberFormat{Type: "CURRENCY"}, -1234.5},
+		{"number", "-12,000", &sheets.NumberFormat{Type: "NUMBER"}, -12000.0},
+		{"scientific", "1.5E+03", &sheets.NumberFormat{Type: "SCIENTIFIC"}, 1500.0},
+		{"percent", "12.5%", &sheets.NumberFormat{Type: "PERCENT"}, 0.125},
+		{"date pattern", "3/7/2024", &sheets.NumberFormat{Type: "DATE", Pattern: "M/d/yyyy"}, time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC)},
+		{"date default", "2024-03-07", &sheets.NumberFormat{Type: "DATE"}, time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC)},
+		{"time", "1:05:09 PM", &sheets.NumberFormat{Type: "TIME", Pattern: "h:mm:ss am/pm"}, time.Date(0, 1, 1, 13, 5, 9, 0, time.UTC)},
+		{"date time", "2024-03-07 13:05", &sheets.NumberFormat{Type: "DATE_TIME", Pattern: "yyyy-MM-dd HH:mm"}, time.Date(2024, 3, 7, 13, 5, 0, 0, time.UTC)},
+		{"text", "hello", &sheets.NumberFormat{Type: "TEXT"}, "hello"},
+		{"no format", "hello", nil, "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFormattedValue(tt.value, tt.format)
+			if err != nil {
+				t.Fatalf("ParseFormattedValue: %v", err)
+			}
+
+			if want, ok := tt.want.(time.Time); ok {
+				if got, ok := got.(time.Time); !ok || !got.Equal(want) {
+					t.Errorf("got %v, want %v", got, want)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("got %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFormattedValueErrors(t *testing.T) {
+	if _, err := ParseFormattedValue("abc", &sheets.NumberFormat{Type: "NUMBER"}); err == nil {
+		t.Error("expected an error parsing a number")
+	}
+	if _, err := ParseFormattedValue("not a date", &sheets.NumberFormat{Type: "DATE"}); err == nil {
+		t.Error("expected an error parsing a date")
+	}
+
+	// Characters that aren't known decorations are errors rather than being dropped.
+	for _, value := range []string{"12abc34", "1.234,56", "1,23", "12,34.5", "1.5,000", "NaN", "Inf", "0x10", "(1,234.50", "1 234 abc", "€1.234.567"} {
+		if got, err := ParseFormattedValue(value, &sheets.NumberFormat{Type: "NUMBER"}); err == nil {
+			t.Errorf("ParseFormattedValue(%q) = %v, want an error", value, got)
+		}
+	}
+}
+
+func TestParseFormattedNumberDecorations(t *testing.T) {
+	tests := map[string]float64{
+		"£ 1,234,567.89": 1234567.89,
+		"¥1,000":         1000,
+		"$ -":            0,
+		"( $ 12.00 )":    -12,
+		"1 234":          1234,
+		"+5":             5,
+		"-0.5":           -0.5,
+	}
+
+	for value, want := range tests {
+		got, err := ParseFormattedValue(value, &sheets.NumberFormat{Type: "CURRENCY"})
+		if err != nil {
+			t.Errorf("ParseFormattedValue(%q): %v", value, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseFormattedValue(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestDateLayout(t *testing.T) {
+	tests := map[string]string{
+		"M/d/yyyy":         "1/2/2006",
+		"yyyy-MM-dd":       "2006-01-02",
+		"mmm d, yyyy":      "Jan 2, 2006",
+		"dddd, mmmm d":     "Monday, January 2",
+		"HH:mm:ss":         "15:04:05",
+		"h:mm am/pm":       "3:04 PM",
+		"mm:ss":            "04:05",
+		"M/d/yy H:mm":      "1/2/06 15:04",
+		"yyyy-mm-dd hh:mm": "2006-01-02 03:04",
+		"m/d/yyyy h:mm:ss": "1/2/2006 3:04:05",
+	}
+
+	for pattern, want := range tests {
+		if got := DateLayout(pattern); got != want {
+			t.Errorf("DateLayout(%q) = %q, want %q", pattern, got, want)
+		}
+	}
+}
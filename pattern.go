@@ -0,0 +1,72 @@
+package rwsheets
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxPatternSections is the number of ";" separated sections a number pattern can have:
+// positive, negative, zero and text.
+const maxPatternSections = 4
+
+var (
+	ErrInvalidPattern = errors.New("invalid number format pattern")
+)
+
+// ValidateNumberPattern: Checks the number format pattern for mistakes Sheets would reject,
+// returning ErrInvalidPattern describing the first one found.
+//
+// Only the syntax is checked locally: quotes and brackets have to be closed, escapes and the
+// "_" and "*" spacing characters need a character after them, and there can be at most four sections.
+// It doesn't guarantee the pattern renders the way you want it to.
+func ValidateNumberPattern(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("%w: pattern is empty", ErrInvalidPattern)
+	}
+
+	runes := []rune(pattern)
+	sections := 1
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '"':
+			end := indexRune(runes, '"', i+1)
+			if end < 0 {
+				return fmt.Errorf("%w: unclosed quote at position %d in %q", ErrInvalidPattern, i, pattern)
+			}
+			i = end
+		case '\\', '_', '*':
+			if i+1 >= len(runes) {
+				return fmt.Errorf("%w: %q at the end of %q needs a character after it", ErrInvalidPattern, r, pattern)
+			}
+			i++
+		case '[':
+			end := indexRune(runes, ']', i+1)
+			if end < 0 {
+				return fmt.Errorf("%w: unclosed bracket at position %d in %q", ErrInvalidPattern, i, pattern)
+			}
+			if end == i+1 {
+				return fmt.Errorf("%w: empty brackets at position %d in %q", ErrInvalidPattern, i, pattern)
+			}
+			i = end
+		case ']':
+			return fmt.Errorf("%w: unopened bracket at position %d in %q", ErrInvalidPattern, i, pattern)
+		case ';':
+			sections++
+			if sections > maxPatternSections {
+				return fmt.Errorf("%w: more than %d sections in %q", ErrInvalidPattern, maxPatternSections, pattern)
+			}
+		}
+	}
+	return nil
+}
+
+// indexRune: Returns the index of the first r in runes at or after start, or -1 if there isn't one.
+func indexRune(runes []rune, r rune, start int) int {
+	for i := start; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,67 @@
+package rwsheets
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateNumberPatternBuiltIn(t *testing.T) {
+	// Every pattern the styler can produce should be accepted.
+	for _, style := range []string{"", NegativeParens, NegativeMinus, NegativeRed} {
+		s := NewStyler()
+		if style != "" {
+			s.NegativeStyle(style)
+		}
+
+		formats := map[string]string{
+			"date":       s.DateFormat().Pattern,
+			"number":     s.NumberFormat().Pattern,
+			"accounting": s.AccountingFormat().Pattern,
+		}
+		if pattern := s.CurrencyFormat().Pattern; pattern != "" {
+			formats["currency"] = pattern
+		}
+
+		for name, pattern := range formats {
+			if err := ValidateNumberPattern(pattern); err != nil {
+				t.Errorf("%s pattern with negative style %q: %v", name, style, err)
+			}
+		}
+	}
+}
+
+func TestValidateNumberPattern(t *testing.T) {
+	valid := []string{
+		"0",
+		"#,##0.00_);(#,##0.00)",
+		`[Red]-#,##0.00`,
+		`"$"#,##0.00;"$"-#,##0.00;"-";@`,
+		`0.00 "units; total"`,
+		`\[0\]`,
+		`* #,##0`,
+		"M/d/yyyy",
+	}
+	for _, pattern := range valid {
+		if err := ValidateNumberPattern(pattern); err != nil {
+			t.Errorf("ValidateNumberPattern(%q) = %v, want nil", pattern, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		" ",
+		`"$#,##0.00`,
+		`[Red#,##0`,
+		`#,##0]`,
+		`[]0`,
+		`0;0;0;0;0`,
+		`0\`,
+		`#,##0.00_`,
+		`0*`,
+	}
+	for _, pattern := range invalid {
+		if err := ValidateNumberPattern(pattern); !errors.Is(err, ErrInvalidPattern) {
+			t.Errorf("ValidateNumberPattern(%q) = %v, want ErrInvalidPattern", pattern, err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package rwsheets
+
+import (
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// QuotaEstimate: struct describing the estimated API quota cost of a call.
+type QuotaEstimate struct {
+	Units       int   // Quota units consumed. Each API call costs one unit no matter how many requests or ranges it contains.
+	SubRequests int   // The number of requests or ranges included in the call.
+	Cells       int64 // Informational. The number of cells written or read, where it can be determined.
+}
+
+// EstimateWriteCost: Estimates the write quota cost of sending the batch update.
+// Cells are counted from the rows of UpdateCells and AppendCells requests and the range of RepeatCell requests.
+func EstimateWriteCost(req *sheets.BatchUpdateSpreadsheetRequest) QuotaEstimate {
+	var estimate QuotaEstimate
+	if req == nil || len(req.Requests) == 0 {
+		return estimate
+	}
+
+	estimate.Units = 1
+	estimate.SubRequests = len(req.Requests)
+	for _, r := range req.Requests {
+		switch {
+		case r.UpdateCells != nil:
+			estimate.Cells += countCells(r.UpdateCells.Rows)
+		case r.AppendCells != nil:
+			estimate.Cells += countCells(r.AppendCells.Rows)
+		case r.RepeatCell != nil:
+			estimate.Cells += rangeCells(r.RepeatCell.Range)
+		}
+	}
+
+	return estimate
+}
+
+// EstimateReadCost: Estimates the read quota cost of reading the A1 ranges with a single batch get.
+// Cells are only counted for ranges with both a row and column bound.
+func EstimateReadCost(ranges []string) QuotaEstimate {
+	var estimate QuotaEstimate
+	if len(ranges) == 0 {
+		return estimate
+	}
+
+	estimate.Units = 1
+	estimate.SubRequests = len(ranges)
+	for _, r := range ranges {
+		if _, gr, err := parseA1(r); err == nil {
+			estimate.Cells += rangeCells(gr)
+		}
+	}
+
+	return estimate
+}
+
+// countCells: Counts the cells in the rows.
+func countCells(rows []*sheets.RowData) int64 {
+	var count int64
+	for _, row := range rows {
+		if row != nil {
+			count += int64(len(row.Values))
+		}
+	}
+	return count
+}
+
+// rangeCells: Counts the cells in the range, or 0 if the range is unbounded.
+func rangeCells(gr *sheets.GridRange) int64 {
+	if gr == nil || gr.EndRowIndex == 0 || gr.EndColumnIndex == 0 {
+		return 0
+	}
+	return (gr.EndRowIndex - gr.StartRowIndex) * (gr.EndColumnIndex - gr.StartColumnIndex)
+}
@@ -0,0 +1,39 @@
+package rwsheets
+
+import (
+	"testing"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestEstimateWriteCost(t *testing.T) {
+	rows := []*sheets.RowData{textRow("a", "b", "c"), textRow("d", "e", "f")}
+	req := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			updateCellsRequest(3, 0, 0, 0, rows, "*"),
+			{AppendCells: &sheets.AppendCellsRequest{Rows: []*sheets.RowData{textRow("g")}}},
+			{RepeatCell: &sheets.RepeatCellRequest{Range: &sheets.GridRange{EndRowIndex: 10, StartColumnIndex: 2, EndColumnIndex: 4}}},
+			// Unbounded ranges and other requests don't add any cells.
+			{RepeatCell: &sheets.RepeatCellRequest{Range: &sheets.GridRange{SheetId: 1}}},
+			{DeleteRange: &sheets.DeleteRangeRequest{}},
+		},
+	}
+
+	got := EstimateWriteCost(req)
+	want := QuotaEstimate{Units: 1, SubRequests: 5, Cells: 6 + 1 + 20}
+	if got != want {
+		t.Errorf("EstimateWriteCost = %+v, want %+v", got, want)
+	}
+
+	if got := EstimateWriteCost(&sheets.BatchUpdateSpreadsheetRequest{}); got != (QuotaEstimate{}) {
+		t.Errorf("empty batch = %+v, want no cost", got)
+	}
+}
+
+func TestEstimateReadCost(t *testing.T) {
+	got := EstimateReadCost([]string{"Sheet1!A1:C10", "B2:B5", "Sheet1", "Data!A:C", "Sheet1!bad:range!"})
+	want := QuotaEstimate{Units: 1, SubRequests: 5, Cells: 30 + 4}
+	if got != want {
+		t.Errorf("EstimateReadCost = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,157 @@
+package rwsheets
+
+import (
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+const (
+	// DefinedNameRange is the DefinedName type for named ranges.
+	DefinedNameRange = "NAMED_RANGE"
+
+	// DefinedNameFunction is the DefinedName type for named functions.
+	DefinedNameFunction = "NAMED_FUNCTION"
+)
+
+// DefinedName: struct describing a name defined in a spreadsheet.
+type DefinedName struct {
+	Name       string
+	Type       string // Either DefinedNameRange or DefinedNameFunction.
+	Reference  string // The A1 range for named ranges or the definition for named functions.
+	NamedRange *sheets.NamedRange
+}
+
+// GetDefinedNames: Retrieve the names defined in the spreadsheet.
+//
+// The Sheets v4 API does not currently expose named functions in the spreadsheet
+// metadata, so only named ranges will be returned until it does.
+func GetDefinedNames(ssid string, srv *sheets.Service) ([]DefinedName, error) {
+	var names []DefinedName
+
+	ss, err := srv.Spreadsheets.Get(ssid).Fields("namedRanges", "sheets.properties(sheetId,title)").Do()
+	if err != nil {
+		return names, err
+	}
+
+	return definedNames(ss), nil
+}
+
+// definedNames: Builds the list of defined names from the spreadsheet metadata.
+func definedNames(ss *sheets.Spreadsheet) []DefinedName {
+	var names []DefinedName
+
+	// Map the sheet IDs to their titles so we can build the A1 references.
+	titles := make(map[int64]string)
+	for _, sheet := range ss.Sheets {
+		if sheet.Properties != nil {
+			titles[sheet.Properties.SheetId] = sheet.Properties.Title
+		}
+	}
+
+	for _, nr := range ss.NamedRanges {
+		var ref string
+		if nr.Range != nil {
+			ref = gridRangeToA1(titles[nr.Range.SheetId], nr.Range)
+		}
+
+		names = append(names, DefinedName{
+			Name:       nr.Name,
+			Type:       DefinedNameRange,
+			Reference:  ref,
+			NamedRange: nr,
+		})
+	}
+
+	return names
+}
+
+// ProtectSpec: struct to be used to describe a protected range.
+type ProtectSpec struct {
+	Range       *sheets.GridRange
+	Description string
+	Editors     []string // Optional. Email addresses of the users allowed to edit the range.
+	WarningOnly bool     // If true, editing the range only shows a warning instead of being blocked.
+
+	// Optional. Ranges within a protected sheet that can still be edited.
+	// Only valid when Range covers the whole sheet.
+	UnprotectedRanges []*sheets.GridRange
+}
+
+// NameSpec: struct to be used to describe a named range.
+type NameSpec struct {
+	Name  string
+	Range *sheets.GridRange
+}
+
+// protectedRangeRequest: Creates the request for adding the protected range described by the spec.
+func protectedRangeRequest(spec ProtectSpec) *sheets.Request {
+	pr := sheets.ProtectedRange{
+		Description:       spec.Description,
+		Range:             spec.Range,
+		UnprotectedRanges: spec.UnprotectedRanges,
+		WarningOnly:       spec.WarningOnly,
+	}
+	if len(spec.Editors) > 0 {
+		pr.Editors = &sheets.Editors{
+			Users: spec.Editors,
+		}
+	}
+
+	return &sheets.Request{
+		AddProtectedRange: &sheets.AddProtectedRangeRequest{
+			ProtectedRange: &pr,
+		},
+	}
+}
+
+// namedRangeRequest: Creates the request for adding the named range described by the spec.
+func namedRangeRequest(spec NameSpec) *sheets.Request {
+	return &sheets.Request{
+		AddNamedRange: &sheets.AddNamedRangeRequest{
+			NamedRange: &sheets.NamedRange{
+				Name:  spec.Name,
+				Range: spec.Range,
+			},
+		},
+	}
+}
+
+// SetupRanges: Adds all of the given protected and named ranges in a single batch update.
+func SetupRanges(ssid string, protected []ProtectSpec, named []NameSpec, srv *sheets.Service) error {
+	var requests []*sheets.Request
+	for _, spec := range protected {
+		requests = append(requests, protectedRangeRequest(spec))
+	}
+	for _, spec := range named {
+		requests = append(requests, namedRangeRequest(spec))
+	}
+
+	if len(requests) == 0 {
+		return nil
+	}
+
+	_, err := batchUpdate(ssid, requests, srv)
+	return err
+}
+
+// ProtectSheet: Protects the whole sheet except for the given unprotected ranges.
+// Returns the ID of the new protected range.
+func ProtectSheet(ssid string, gid int64, description string, unprotectedRanges []*sheets.GridRange, editors []string, srv *sheets.Service) (int64, error) {
+	spec := ProtectSpec{
+		Range: &sheets.GridRange{
+			SheetId: gid,
+		},
+		Description:       description,
+		Editors:           editors,
+		UnprotectedRanges: unprotectedRanges,
+	}
+
+	resp, err := batchUpdate(ssid, []*sheets.Request{protectedRangeRequest(spec)}, srv)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(resp.Replies) == 0 || resp.Replies[0].AddProtectedRange == nil || resp.Replies[0].AddProtectedRange.ProtectedRange == nil {
+		return 0, ErrNoData
+	}
+	return resp.Replies[0].AddProtectedRange.ProtectedRange.ProtectedRangeId, nil
+}
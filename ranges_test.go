@@ -0,0 +1,167 @@
+package rwsheets
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestGetDefinedNames(t *testing.T) {
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusOK, &sheets.Spreadsheet{
+			NamedRanges: []*sheets.NamedRange{
+				{
+					Name: "Totals",
+					Range: &sheets.GridRange{
+						SheetId:          7,
+						StartRowIndex:    1,
+						EndRowIndex:      10,
+						StartColumnIndex: 1,
+						EndColumnIndex:   3,
+					},
+				},
+				{
+					Name:  "WholeSheet",
+					Range: &sheets.GridRange{SheetId: 0},
+				},
+			},
+			Sheets: []*sheets.Sheet{
+				{Properties: &sheets.SheetProperties{SheetId: 0, Title: "Summary Data"}},
+				{Properties: &sheets.SheetProperties{SheetId: 7, Title: "My Data"}},
+			},
+		}
+	})
+
+	names, err := GetDefinedNames("ssid", srv)
+	if err != nil {
+		t.Fatalf("GetDefinedNames: %v", err)
+	}
+
+	want := []DefinedName{
+		{Name: "Totals", Type: DefinedNameRange, Reference: "'My Data'!B2:C10"},
+		{Name: "WholeSheet", Type: DefinedNameRange, Reference: "'Summary Data'"},
+	}
+	if len(names) != len(want) {
+		t.Fatalf("got %d names, want %d", len(names), len(want))
+	}
+	for i, name := range names {
+		if name.Name != want[i].Name || name.Type != want[i].Type || name.Reference != want[i].Reference {
+			t.Errorf("name %d = %+v, want %+v", i, name, want[i])
+		}
+		if name.NamedRange == nil {
+			t.Errorf("name %d is missing its named range", i)
+		}
+	}
+
+	if got := api.Calls()[0].Query.Get("fields"); got != "namedRanges,sheets.properties(sheetId,title)" {
+		t.Errorf("fields = %q", got)
+	}
+}
+
+func TestGetDefinedNamesEmpty(t *testing.T) {
+	srv, _ := newFakeService(t, nil)
+
+	names, err := GetDefinedNames("ssid", srv)
+	if err != nil {
+		t.Fatalf("GetDefinedNames: %v", err)
+	}
+	if len(names) != 0 {
+		t.Errorf("got %d names, want none", len(names))
+	}
+}
+
+func TestSetupRanges(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	protected := []ProtectSpec{
+		{Range: &sheets.GridRange{SheetId: 1, EndRowIndex: 1}, Description: "header", Editors: []string{"a@example.com"}},
+		{Range: &sheets.GridRange{SheetId: 2}, WarningOnly: true},
+	}
+	named := []NameSpec{
+		{Name: "Inputs", Range: &sheets.GridRange{SheetId: 1, StartRowIndex: 1}},
+	}
+
+	if err := SetupRanges("ssid", protected, named, srv); err != nil {
+		t.Fatalf("SetupRanges: %v", err)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 3 {
+		t.Fatalf("got %d requests, want 3", len(requests))
+	}
+
+	for i, request := range requests[:2] {
+		if request.AddProtectedRange == nil {
+			t.Fatalf("request %d is not an AddProtectedRange", i)
+		}
+	}
+	if pr := requests[0].AddProtectedRange.ProtectedRange; pr.Description != "header" || len(pr.Editors.Users) != 1 {
+		t.Errorf("first protected range = %+v", pr)
+	}
+	if pr := requests[1].AddProtectedRange.ProtectedRange; !pr.WarningOnly || pr.Editors != nil {
+		t.Errorf("second protected range = %+v", pr)
+	}
+	if nr := requests[2].AddNamedRange; nr == nil || nr.NamedRange.Name != "Inputs" {
+		t.Errorf("third request = %+v, want the Inputs named range", requests[2])
+	}
+}
+
+func TestSetupRangesEmpty(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	if err := SetupRanges("ssid", nil, nil, srv); err != nil {
+		t.Fatalf("SetupRanges: %v", err)
+	}
+	if calls := api.Calls(); len(calls) != 0 {
+		t.Errorf("got %d calls, want none", len(calls))
+	}
+}
+
+func TestProtectSheet(t *testing.T) {
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusOK, &sheets.BatchUpdateSpreadsheetResponse{
+			Replies: []*sheets.Response{{
+				AddProtectedRange: &sheets.AddProtectedRangeResponse{
+					ProtectedRange: &sheets.ProtectedRange{ProtectedRangeId: 99},
+				},
+			}},
+		}
+	})
+
+	inputs := []*sheets.GridRange{
+		{SheetId: 4, StartRowIndex: 1, EndRowIndex: 10, StartColumnIndex: 1, EndColumnIndex: 2},
+	}
+	id, err := ProtectSheet("ssid", 4, "locked", inputs, []string{"owner@example.com"}, srv)
+	if err != nil {
+		t.Fatalf("ProtectSheet: %v", err)
+	}
+	if id != 99 {
+		t.Errorf("id = %d, want 99", id)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 1 || requests[0].AddProtectedRange == nil {
+		t.Fatalf("requests = %+v, want a single AddProtectedRange", requests)
+	}
+
+	pr := requests[0].AddProtectedRange.ProtectedRange
+	if r := pr.Range; r.SheetId != 4 || r.EndRowIndex != 0 || r.EndColumnIndex != 0 {
+		t.Errorf("range = %+v, want the whole sheet", r)
+	}
+	if len(pr.UnprotectedRanges) != 1 || pr.UnprotectedRanges[0].EndRowIndex != 10 {
+		t.Errorf("unprotected ranges = %+v", pr.UnprotectedRanges)
+	}
+	if pr.Description != "locked" || pr.Editors == nil || pr.Editors.Users[0] != "owner@example.com" {
+		t.Errorf("protected range = %+v", pr)
+	}
+}
+
+func TestProtectSheetNoReply(t *testing.T) {
+	srv, _ := newFakeService(t, nil)
+
+	if _, err := ProtectSheet("ssid", 4, "", nil, nil, srv); !errors.Is(err, ErrNoData) {
+		t.Errorf("err = %v, want ErrNoData", err)
+	}
+}
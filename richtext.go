@@ -0,0 +1,115 @@
+package rwsheets
+
+import (
+	"strings"
+	"unicode/utf16"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// textLength: Returns the length of the text in UTF-16 code units, which is how the API indexes text format runs.
+// This matches the rune count for most text, but characters outside the Basic Multilingual Plane,
+// such as emoji, count as two.
+func textLength(s string) int64 {
+	return int64(len(utf16.Encode([]rune(s))))
+}
+
+// RichTextBuilder is to be used to create a single cell containing text with embedded links.
+type RichTextBuilder struct {
+	text   strings.Builder
+	length int64
+	runs   []*sheets.TextFormatRun
+}
+
+// NewRichTextBuilder: Returns a new empty rich text builder.
+func NewRichTextBuilder() *RichTextBuilder {
+	return &RichTextBuilder{}
+}
+
+// addRun: Adds the text to the cell, starting a new text format run with the given format.
+func (b *RichTextBuilder) addRun(s string, format *sheets.TextFormat) *RichTextBuilder {
+	if s == "" {
+		return b
+	}
+
+	b.runs = append(b.runs, &sheets.TextFormatRun{
+		Format:     format,
+		StartIndex: b.length,
+	})
+	b.text.WriteString(s)
+	b.length += textLength(s)
+	return b
+}
+
+// AddText: Adds plain text to the cell.
+func (b *RichTextBuilder) AddText(s string) *RichTextBuilder {
+	return b.addRun(s, &sheets.TextFormat{})
+}
+
+// AddLink: Adds text to the cell that links to the given url.
+func (b *RichTextBuilder) AddLink(s, url string) *RichTextBuilder {
+	return b.addRun(s, &sheets.TextFormat{
+		Link: &sheets.Link{
+			Uri: url,
+		},
+	})
+}
+
+// String: Returns the text added to the builder so far.
+func (b *RichTextBuilder) String() string {
+	return b.text.String()
+}
+
+// Cell: Creates a new sheets cell with the builders text and format runs.
+func (b *RichTextBuilder) Cell() *sheets.CellData {
+	return &sheets.CellData{
+		TextFormatRuns:   b.runs,
+		UserEnteredValue: TextValue(b.text.String()),
+	}
+}
+
+// TextRun: struct to be used to describe a piece of text with its own formatting in a RichTextCell.
+type TextRun struct {
+	Text   string
+	Bold   bool
+	Italic bool
+	Color  *sheets.ColorStyle // Optional. Defaults to the cells text color.
+}
+
+// RichTextCell: Creates a new sheets text cell with each run formatted separately,
+// using the stylers settings for the rest of the formatting. Empty runs are skipped.
+//
+// The run start indices count UTF-16 code units, as the API expects, so text with
+// multibyte characters or emoji keeps its run boundaries in the right place.
+func (s *Styler) RichTextCell(runs []TextRun, borders *BorderConf) *sheets.CellData {
+	var (
+		text       strings.Builder
+		length     int64
+		formatRuns []*sheets.TextFormatRun
+	)
+
+	for _, run := range runs {
+		if run.Text == "" {
+			continue
+		}
+
+		formatRuns = append(formatRuns, &sheets.TextFormatRun{
+			Format: &sheets.TextFormat{
+				Bold:                 run.Bold,
+				ForegroundColorStyle: run.Color,
+				Italic:               run.Italic,
+				// Bold and Italic would otherwise be dropped when false, and the run would keep the cells style.
+				ForceSendFields: []string{"Bold", "Italic"},
+			},
+			StartIndex: length,
+		})
+		text.WriteString(run.Text)
+		length += textLength(run.Text)
+	}
+
+	return &sheets.CellData{
+		TextFormatRuns:    formatRuns,
+		UserEnteredFormat: s.cellFormat(nil, borders),
+		UserEnteredValue:  TextValue(text.String()),
+	}
+}
@@ -0,0 +1,105 @@
+package rwsheets
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestRichTextBuilder(t *testing.T) {
+	cell := NewRichTextBuilder().
+		AddText("See ").
+		AddLink("the docs", "https://example.com/docs").
+		AddText(" or ").
+		AddLink("日本 😀", "https://example.com/jp").
+		AddText("!").
+		Cell()
+
+	if got := *cell.UserEnteredValue.StringValue; got != "See the docs or 日本 😀!" {
+		t.Errorf("text = %q", got)
+	}
+
+	want := []struct {
+		start int64
+		link  string
+	}{
+		{0, ""},
+		{4, "https://example.com/docs"},
+		{12, ""},
+		{16, "https://example.com/jp"},
+		// "日本 😀" is 3 UTF-16 code units plus 2 for the emoji.
+		{21, ""},
+	}
+	if len(cell.TextFormatRuns) != len(want) {
+		t.Fatalf("got %d runs, want %d", len(cell.TextFormatRuns), len(want))
+	}
+	for i, run := range cell.TextFormatRuns {
+		var link string
+		if run.Format.Link != nil {
+			link = run.Format.Link.Uri
+		}
+		if run.StartIndex != want[i].start || link != want[i].link {
+			t.Errorf("run %d = %d %q, want %d %q", i, run.StartIndex, link, want[i].start, want[i].link)
+		}
+	}
+}
+
+func TestRichTextBuilderSkipsEmptyText(t *testing.T) {
+	b := NewRichTextBuilder().AddText("").AddLink("", "https://example.com").AddText("x")
+	if runs := b.Cell().TextFormatRuns; len(runs) != 1 {
+		t.Errorf("got %d runs, want 1", len(runs))
+	}
+	if b.String() != "x" {
+		t.Errorf("String() = %q, want x", b.String())
+	}
+}
+
+func TestStylerRichTextCell(t *testing.T) {
+	red := &sheets.ColorStyle{RgbColor: &sheets.Color{Red: 1}}
+	runs := []TextRun{
+		{Text: "Total: ", Bold: true},
+		{Text: ""},
+		{Text: "café ", Italic: true},
+		{Text: "🎉 done", Color: red},
+	}
+
+	cell := NewStyler().RichTextCell(runs, nil)
+
+	if v := cell.UserEnteredValue; v.StringValue == nil || *v.StringValue != "Total: café 🎉 done" {
+		t.Fatalf("value = %+v", v)
+	}
+	if cell.UserEnteredFormat == nil {
+		t.Error("the stylers formatting wasn't used")
+	}
+
+	// "é" is one code unit, so the last run starts at 7 + 5. The empty run is skipped.
+	want := []struct {
+		start        int64
+		bold, italic bool
+		color        bool
+	}{
+		{0, true, false, false},
+		{7, false, true, false},
+		{12, false, false, true},
+	}
+	if len(cell.TextFormatRuns) != len(want) {
+		t.Fatalf("got %d runs, want %d", len(cell.TextFormatRuns), len(want))
+	}
+	for i, w := range want {
+		run := cell.TextFormatRuns[i]
+		if run.StartIndex != w.start || run.Format.Bold != w.bold || run.Format.Italic != w.italic || (run.Format.ForegroundColorStyle != nil) != w.color {
+			t.Errorf("run %d = start %d %+v, want %+v", i, run.StartIndex, run.Format, w)
+		}
+	}
+
+	// Plain runs still send Bold and Italic, so they don't take on the cells style.
+	body, err := json.Marshal(cell.TextFormatRuns[2].Format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), `"bold":false`) || !strings.Contains(string(body), `"italic":false`) {
+		t.Errorf("format %s is missing bold or italic", body)
+	}
+}
@@ -0,0 +1,446 @@
+package rwsheets
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// cellAt: Returns the cell at the given column of the row, or nil if the row doesn't have one.
+func cellAt(row *sheets.RowData, col int) *sheets.CellData {
+	if row == nil || col < 0 || col >= len(row.Values) {
+		return nil
+	}
+	return row.Values[col]
+}
+
+// cellString: Returns the string value of the cell.
+// The formatted value is preferred, followed by the effective and then user entered values.
+func cellString(c *sheets.CellData) string {
+	if c == nil {
+		return ""
+	}
+	if c.FormattedValue != "" {
+		return c.FormattedValue
+	}
+
+	value := c.EffectiveValue
+	if value == nil {
+		value = c.UserEnteredValue
+	}
+	if value == nil {
+		return ""
+	}
+
+	switch {
+	case value.StringValue != nil:
+		return *value.StringValue
+	case value.NumberValue != nil:
+		return strconv.FormatFloat(*value.NumberValue, 'f', -1, 64)
+	case value.BoolValue != nil:
+		return strconv.FormatBool(*value.BoolValue)
+	case value.FormulaValue != nil:
+		return *value.FormulaValue
+	}
+	return ""
+}
+
+// cellNumber: Returns the number value of the cell, and whether the cell held a number.
+func cellNumber(c *sheets.CellData) (float64, bool) {
+	if c == nil {
+		return float64(0.0), false
+	}
+	if c.EffectiveValue != nil && c.EffectiveValue.NumberValue != nil {
+		return *c.EffectiveValue.NumberValue, true
+	}
+	if c.UserEnteredValue != nil && c.UserEnteredValue.NumberValue != nil {
+		return *c.UserEnteredValue.NumberValue, true
+	}
+	return float64(0.0), false
+}
+
+// AppendSubtotals: Inserts a subtotal row after each group of rows summing the value column.
+//
+// The rows are expected to already be sorted by the group column. Each subtotal row has
+// "<group> Total" in the group column and the sum in the value column, created with the styler.
+// If either column is negative, or they are the same column so the sum would overwrite the label,
+// the rows are returned unchanged.
+func AppendSubtotals(rows []*sheets.RowData, groupCol, valueCol int, styler *Styler, borders *BorderConf) []*sheets.RowData {
+	var result []*sheets.RowData
+	if len(rows) == 0 {
+		return result
+	}
+	if groupCol < 0 || valueCol < 0 || groupCol == valueCol {
+		return rows
+	}
+
+	width := groupCol + 1
+	if valueCol >= width {
+		width = valueCol + 1
+	}
+
+	subtotal := func(group string, sum float64) *sheets.RowData {
+		cells := make([]*sheets.CellData, width)
+		for i := range cells {
+			cells[i] = &sheets.CellData{}
+		}
+		cells[groupCol] = styler.TextCell(group+" Total", borders)
+		cells[valueCol] = styler.NumberCell(sum, borders)
+		return &sheets.RowData{
+			Values: cells,
+		}
+	}
+
+	group := cellString(cellAt(rows[0], groupCol))
+	var sum float64
+	for _, row := range rows {
+		key := cellString(cellAt(row, groupCol))
+		if key != group {
+			result = append(result, subtotal(group, sum))
+			group = key
+			sum = 0
+		}
+
+		if value, ok := cellNumber(cellAt(row, valueCol)); ok {
+			sum += value
+		}
+		result = append(result, row)
+	}
+	result = append(result, subtotal(group, sum))
+
+	return result
+}
+
+// BuildSectionedRows: Builds the rows for a report with a section title row before each group of values.
+// The groups are written in sorted key order, with the key as the title created with the header styler
+// and the values created with the body styler. Title rows span the width of the widest row.
+//
+// The returned merge requests merge each title row across that width. Their ranges are relative to
+// the first row and column of the rows on sheet 0, so offset them and set the sheet ID to match where
+// the rows are written, then send them after writing the rows, for example with UpdateSheetData.
+func BuildSectionedRows(groups map[string][][]interface{}, headerStyler, bodyStyler *Styler, borders *BorderConf) ([]*sheets.RowData, []*sheets.Request) {
+	var keys []string
+	width := 1
+	for key, values := range groups {
+		keys = append(keys, key)
+		for _, row := range values {
+			width = max(width, len(row))
+		}
+	}
+	sort.Strings(keys)
+
+	var rows []*sheets.RowData
+	var merges []*sheets.Request
+	for _, key := range keys {
+		title := make([]*sheets.CellData, width)
+		title[0] = headerStyler.TextCell(key, borders)
+		for i := 1; i < width; i++ {
+			title[i] = headerStyler.TextCell("", borders)
+		}
+
+		if width > 1 {
+			merges = append(merges, &sheets.Request{
+				MergeCells: &sheets.MergeCellsRequest{
+					MergeType: "MERGE_ALL",
+					Range: &sheets.GridRange{
+						StartRowIndex:  int64(len(rows)),
+						EndRowIndex:    int64(len(rows)) + 1,
+						EndColumnIndex: int64(width),
+					},
+				},
+			})
+		}
+		rows = append(rows, &sheets.RowData{Values: title})
+
+		for _, values := range groups[key] {
+			var cells []*sheets.CellData
+			for _, value := range values {
+				cells = append(cells, bodyStyler.valueCell(value, borders))
+			}
+			rows = append(rows, &sheets.RowData{Values: cells})
+		}
+	}
+
+	return rows, merges
+}
+
+// valueCell: Creates a new cell for the value based on its type using the stylers settings for the formatting.
+// Strings become text, bools become checkboxes, numbers use the stylers number format and time.Time values its
+// date format, unless a format was registered for the type with RegisterTypeFormat. Pointers are followed,
+// and anything else is written as its default text representation.
+func (s *Styler) valueCell(value interface{}, borders *BorderConf) *sheets.CellData {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || rv.Kind() == reflect.Pointer {
+		return &sheets.CellData{UserEnteredFormat: s.cellFormat(nil, borders)}
+	}
+
+	nf := TypeFormat(rv.Type())
+	if t, ok := rv.Interface().(time.Time); ok {
+		if nf == nil {
+			nf = s.DateFormat()
+		}
+		return s.FormattedNumberCell(timeToSerial(t), nf, borders)
+	}
+
+	var number float64
+	switch rv.Kind() {
+	case reflect.String:
+		return s.TextCell(rv.String(), borders)
+	case reflect.Bool:
+		return s.CheckBoxCell(rv.Bool(), borders)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number = float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		number = float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		number = rv.Float()
+	default:
+		return s.TextCell(fmt.Sprint(rv.Interface()), borders)
+	}
+
+	if nf == nil {
+		nf = s.NumberFormat()
+	}
+	return s.FormattedNumberCell(number, nf, borders)
+}
+
+// cellValue: Returns the effective value of the cell as a string, float64 or bool.
+// Returns nil for empty cells.
+func cellValue(c *sheets.CellData) interface{} {
+	if c == nil {
+		return nil
+	}
+
+	value := c.EffectiveValue
+	if value == nil {
+		value = c.UserEnteredValue
+	}
+	if value == nil {
+		return nil
+	}
+
+	switch {
+	case value.StringValue != nil:
+		return *value.StringValue
+	case value.NumberValue != nil:
+		return *value.NumberValue
+	case value.BoolValue != nil:
+		return *value.BoolValue
+	case value.FormulaValue != nil:
+		return *value.FormulaValue
+	case value.ErrorValue != nil:
+		return value.ErrorValue.Type
+	}
+	return nil
+}
+
+// asciiCellWidth is the widest a cell can be in RowsToASCII before it is truncated.
+const asciiCellWidth = 40
+
+// RowsToASCII: Renders the rows as an aligned, bordered text table for logging and debugging.
+// Short rows are padded with empty cells and cells wider than 40 characters are truncated with an ellipsis.
+func RowsToASCII(rows []*sheets.RowData) string {
+	var table [][]string
+	var widths []int
+	for _, row := range rows {
+		var cells []string
+		if row != nil {
+			for i, cell := range row.Values {
+				value := strings.ReplaceAll(cellString(cell), "\n", " ")
+				if utf8.RuneCountInString(value) > asciiCellWidth {
+					value = string([]rune(value)[:asciiCellWidth-1]) + "…"
+				}
+				cells = append(cells, value)
+
+				if i >= len(widths) {
+					widths = append(widths, 0)
+				}
+				widths[i] = max(widths[i], utf8.RuneCountInString(value))
+			}
+		}
+		table = append(table, cells)
+	}
+
+	var border strings.Builder
+	border.WriteString("+")
+	for _, w := range widths {
+		border.WriteString(strings.Repeat("-", w+2) + "+")
+	}
+	border.WriteString("\n")
+
+	var b strings.Builder
+	b.WriteString(border.String())
+	for _, cells := range table {
+		b.WriteString("|")
+		for i, w := range widths {
+			var value string
+			if i < len(cells) {
+				value = cells[i]
+			}
+			b.WriteString(" " + value + strings.Repeat(" ", w-utf8.RuneCountInString(value)) + " |")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(border.String())
+
+	return b.String()
+}
+
+// IsHeaderRow: The default header row heuristic used by DetectHeaderRow.
+// A row is a likely header if it spans the full width of the data and every cell holds non-empty text.
+func IsHeaderRow(row *sheets.RowData, width int) bool {
+	if row == nil || width == 0 || rowWidth(row) != width {
+		return false
+	}
+
+	for _, cell := range row.Values[:width] {
+		if _, ok := cellValue(cell).(string); !ok || strings.TrimSpace(cellString(cell)) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// DetectHeaderRow: Returns the index of the first row that looks like a header row, or -1 if there isn't one.
+// This skips any title or blank rows above the header. See IsHeaderRow for the heuristic used.
+func DetectHeaderRow(rows []*sheets.RowData) int {
+	return DetectHeaderRowFunc(rows, IsHeaderRow)
+}
+
+// DetectHeaderRowFunc: Returns the index of the first row isHeader reports as a header, or -1 if there isn't one.
+// width is the number of columns in the widest row, ignoring trailing empty cells.
+func DetectHeaderRowFunc(rows []*sheets.RowData, isHeader func(row *sheets.RowData, width int) bool) int {
+	width := 0
+	for _, row := range rows {
+		width = max(width, rowWidth(row))
+	}
+
+	for i, row := range rows {
+		if isHeader(row, width) {
+			return i
+		}
+	}
+	return -1
+}
+
+// rowWidth: Returns the number of cells in the row, ignoring trailing empty cells.
+func rowWidth(row *sheets.RowData) int {
+	if row == nil {
+		return 0
+	}
+
+	width := len(row.Values)
+	for width > 0 && cellValue(row.Values[width-1]) == nil {
+		width--
+	}
+	return width
+}
+
+// MaxColumns: Returns the number of cells in the widest row.
+func MaxColumns(rows []*sheets.RowData) int64 {
+	var width int
+	for _, row := range rows {
+		if row != nil {
+			width = max(width, len(row.Values))
+		}
+	}
+	return int64(width)
+}
+
+// findRowByKey: Returns the index of the first row whose key column has the keys string value, or -1 if none do.
+func findRowByKey(rows []*sheets.RowData, key string, keyColumnIndex int) int {
+	for i, row := range rows {
+		if cell := cellAt(row, keyColumnIndex); cell != nil && cellString(cell) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// PadRows: Pads every row with empty cells so they all have the same number of cells.
+// If width is 0 or less, the rows are padded to the width of the widest row.
+func PadRows(rows []*sheets.RowData, width int) []*sheets.RowData {
+	if width <= 0 {
+		width = int(MaxColumns(rows))
+	}
+
+	for i, row := range rows {
+		if row == nil {
+			row = &sheets.RowData{}
+			rows[i] = row
+		}
+		for len(row.Values) < width {
+			row.Values = append(row.Values, &sheets.CellData{})
+		}
+	}
+	return rows
+}
+
+// PreserveRoundTrip: Returns copies of the rows keeping only what a user can set on each cell,
+// so the rows can be written back without numbers turning into text or dates losing their format.
+//
+// The rows should be read with at least the "userEnteredValue,userEnteredFormat" cell fields, which
+// GetSheetData includes. Cells read without a user entered value or format fall back to their
+// effective value and effective number format. Write the rows back with the
+// "userEnteredValue,userEnteredFormat,note,dataValidation,textFormatRuns" fields mask.
+func PreserveRoundTrip(rows []*sheets.RowData) []*sheets.RowData {
+	var result []*sheets.RowData
+	for _, row := range rows {
+		var cells []*sheets.CellData
+		if row != nil {
+			for _, cell := range row.Values {
+				cells = append(cells, roundTripCell(cell))
+			}
+		}
+		result = append(result, &sheets.RowData{Values: cells})
+	}
+	return result
+}
+
+// roundTripCell: Returns a copy of the cell with only its user settable fields.
+func roundTripCell(c *sheets.CellData) *sheets.CellData {
+	if c == nil {
+		return &sheets.CellData{}
+	}
+
+	cell := sheets.CellData{
+		DataValidation:    c.DataValidation,
+		Note:              c.Note,
+		TextFormatRuns:    c.TextFormatRuns,
+		UserEnteredFormat: c.UserEnteredFormat,
+		UserEnteredValue:  c.UserEnteredValue,
+	}
+
+	if cell.UserEnteredValue == nil && c.EffectiveValue != nil {
+		value := *c.EffectiveValue
+		value.ErrorValue = nil
+		cell.UserEnteredValue = &value
+	}
+	if cell.UserEnteredFormat == nil && c.EffectiveFormat != nil && c.EffectiveFormat.NumberFormat != nil {
+		cell.UserEnteredFormat = &sheets.CellFormat{
+			NumberFormat: c.EffectiveFormat.NumberFormat,
+		}
+	}
+
+	return &cell
+}
+
+// GroupRowsByColumn: Groups the rows by the string value of the given column, keeping the rows order within each group.
+// Rows that are missing the column or have it empty are grouped under "".
+func GroupRowsByColumn(rows []*sheets.RowData, columnIndex int) map[string][]*sheets.RowData {
+	groups := make(map[string][]*sheets.RowData)
+	for _, row := range rows {
+		key := cellString(cellAt(row, columnIndex))
+		groups[key] = append(groups[key], row)
+	}
+	return groups
+}
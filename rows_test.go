@@ -0,0 +1,293 @@
+package rwsheets
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// groupRow: Returns a row with the group name in the first column and the value in the second.
+func groupRow(group string, value float64) *sheets.RowData {
+	return &sheets.RowData{
+		Values: []*sheets.CellData{
+			{UserEnteredValue: TextValue(group)},
+			{UserEnteredValue: NumberValue(value)},
+		},
+	}
+}
+
+func TestAppendSubtotals(t *testing.T) {
+	rows := []*sheets.RowData{
+		groupRow("a", 1),
+		groupRow("a", 2),
+		groupRow("b", 10),
+		groupRow("c", 100),
+		groupRow("c", 200),
+		groupRow("c", 300),
+	}
+
+	result := AppendSubtotals(rows, 0, 1, NewStyler(), nil)
+
+	want := []struct {
+		group string
+		value float64
+	}{
+		{"a", 1}, {"a", 2}, {"a Total", 3},
+		{"b", 10}, {"b Total", 10},
+		{"c", 100}, {"c", 200}, {"c", 300}, {"c Total", 600},
+	}
+	if len(result) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(result), len(want))
+	}
+
+	for i, row := range result {
+		group := cellString(cellAt(row, 0))
+		value, _ := cellNumber(cellAt(row, 1))
+		if group != want[i].group || value != want[i].value {
+			t.Errorf("row %d = %q %v, want %q %v", i, group, value, want[i].group, want[i].value)
+		}
+	}
+
+	// The subtotal rows should be styled like any other number cell.
+	if format := cellAt(result[2], 1).UserEnteredFormat; format == nil || format.NumberFormat == nil {
+		t.Error("subtotal cell is missing its number format")
+	}
+}
+
+func TestAppendSubtotalsEmpty(t *testing.T) {
+	if result := AppendSubtotals(nil, 0, 1, NewStyler(), nil); len(result) != 0 {
+		t.Errorf("got %d rows, want none", len(result))
+	}
+}
+
+func TestAppendSubtotalsInvalidColumns(t *testing.T) {
+	rows := []*sheets.RowData{groupRow("a", 1), groupRow("b", 2)}
+
+	tests := []struct {
+		name               string
+		groupCol, valueCol int
+	}{
+		{"negative group column", -1, 1},
+		{"negative value column", 0, -1},
+		{"same column", 1, 1},
+	}
+	for _, tt := range tests {
+		result := AppendSubtotals(rows, tt.groupCol, tt.valueCol, NewStyler(), nil)
+		if len(result) != len(rows) || result[0] != rows[0] || result[1] != rows[1] {
+			t.Errorf("%s: got %d rows, want the rows unchanged", tt.name, len(result))
+		}
+	}
+}
+
+func TestRowsToASCII(t *testing.T) {
+	rows := []*sheets.RowData{
+		textRow("Name", "Qty"),
+		textRow("apples", "3", "extra"),
+		textRow("kiwi"),
+		textRow(strings.Repeat("x", 45)),
+	}
+
+	want := strings.Join([]string{
+		"+------------------------------------------+-----+-------+",
+		"| Name                                     | Qty |       |",
+		"| apples                                   | 3   | extra |",
+		"| kiwi                                     |     |       |",
+		"| " + strings.Repeat("x", 39) + "… |     |       |",
+		"+------------------------------------------+-----+-------+",
+	}, "\n") + "\n"
+
+	if got := RowsToASCII(rows); got != want {
+		t.Errorf("RowsToASCII =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDetectHeaderRow(t *testing.T) {
+	rows := []*sheets.RowData{
+		textRow("Quarterly Report"),
+		{},
+		textRow("Name", "Region", "Total"),
+		{Values: []*sheets.CellData{{UserEnteredValue: TextValue("a")}, {UserEnteredValue: TextValue("east")}, {UserEnteredValue: NumberValue(3)}}},
+	}
+
+	if got := DetectHeaderRow(rows); got != 2 {
+		t.Errorf("DetectHeaderRow = %d, want 2", got)
+	}
+
+	// Rows with numbers or blanks aren't headers.
+	if got := DetectHeaderRow(rows[3:]); got != -1 {
+		t.Errorf("DetectHeaderRow = %d, want -1", got)
+	}
+	if got := DetectHeaderRow(nil); got != -1 {
+		t.Errorf("DetectHeaderRow(nil) = %d, want -1", got)
+	}
+}
+
+func TestDetectHeaderRowFunc(t *testing.T) {
+	rows := []*sheets.RowData{
+		textRow("Quarterly Report"),
+		textRow("Name", "Region"),
+	}
+
+	// Treat any row starting with "Quarterly" as the header instead.
+	got := DetectHeaderRowFunc(rows, func(row *sheets.RowData, width int) bool {
+		return strings.HasPrefix(cellString(cellAt(row, 0)), "Quarterly")
+	})
+	if got != 0 {
+		t.Errorf("DetectHeaderRowFunc = %d, want 0", got)
+	}
+}
+
+func TestPreserveRoundTrip(t *testing.T) {
+	// A number cell as it comes back from the API, with its computed fields.
+	read := NewStyler().NumberCell(1234.5, nil)
+	read.EffectiveValue = NumberValue(1234.5)
+	read.FormattedValue = "1,234.50"
+	read.EffectiveFormat = read.UserEnteredFormat
+
+	rows := PreserveRoundTrip([]*sheets.RowData{{Values: []*sheets.CellData{read}}, nil})
+	if len(rows) != 2 || len(rows[1].Values) != 0 {
+		t.Fatalf("rows = %+v", rows)
+	}
+
+	cell := rows[0].Values[0]
+	if v := cell.UserEnteredValue; v.NumberValue == nil || *v.NumberValue != 1234.5 {
+		t.Errorf("value = %+v, want the number 1234.5", v)
+	}
+	if nf := cell.UserEnteredFormat.NumberFormat; nf.Type != "NUMBER" || nf.Pattern != "#,##0.00_);-#,##0.00" {
+		t.Errorf("number format = %+v", nf)
+	}
+	if cell.EffectiveValue != nil || cell.FormattedValue != "" || cell.EffectiveFormat != nil {
+		t.Errorf("computed fields were kept: %+v", cell)
+	}
+}
+
+func TestPreserveRoundTripEffectiveOnly(t *testing.T) {
+	// Cells read with only their effective fields fall back to them.
+	read := &sheets.CellData{
+		EffectiveValue:  NumberValue(45000),
+		EffectiveFormat: &sheets.CellFormat{NumberFormat: DateFormat("M/d/yyyy")},
+	}
+
+	cell := PreserveRoundTrip([]*sheets.RowData{{Values: []*sheets.CellData{read}}})[0].Values[0]
+	if v := cell.UserEnteredValue; v.NumberValue == nil || *v.NumberValue != 45000 {
+		t.Errorf("value = %+v, want the number 45000", v)
+	}
+	if nf := cell.UserEnteredFormat.NumberFormat; nf.Type != "DATE" {
+		t.Errorf("number format = %+v, want the date format", nf)
+	}
+}
+
+func TestGroupRowsByColumn(t *testing.T) {
+	a1, a2 := groupRow("a", 1), groupRow("a", 2)
+	b := groupRow("b", 3)
+	c := groupRow("c", 4)
+	short := &sheets.RowData{}
+	blank := groupRow("", 5)
+
+	groups := GroupRowsByColumn([]*sheets.RowData{a1, b, a2, short, c, blank}, 0)
+
+	if len(groups) != 4 {
+		t.Fatalf("got %d groups, want 4", len(groups))
+	}
+	if got := groups["a"]; len(got) != 2 || got[0] != a1 || got[1] != a2 {
+		t.Errorf("group a = %v, want both a rows in order", got)
+	}
+	if got := groups["b"]; len(got) != 1 || got[0] != b {
+		t.Errorf("group b = %v", got)
+	}
+	if got := groups["c"]; len(got) != 1 || got[0] != c {
+		t.Errorf("group c = %v", got)
+	}
+	if got := groups[""]; len(got) != 2 || got[0] != short || got[1] != blank {
+		t.Errorf("group \"\" = %v, want the rows missing the column", got)
+	}
+
+	// A column past the end of every row puts them all under "".
+	if groups := GroupRowsByColumn([]*sheets.RowData{a1, b}, 5); len(groups) != 1 || len(groups[""]) != 2 {
+		t.Errorf("groups = %v, want every row under \"\"", groups)
+	}
+}
+
+func TestRemoveRow(t *testing.T) {
+	tests := []struct {
+		name  string
+		rows  []string
+		index int
+		want  string
+	}{
+		{"first", []string{"a", "b", "c"}, 0, "b|c"},
+		{"middle", []string{"a", "b", "c"}, 1, "a|c"},
+		{"last", []string{"a", "b", "c"}, 2, "a|b"},
+		{"only", []string{"a"}, 0, ""},
+		{"past the end", []string{"a", "b", "c"}, 3, "a|b|c"},
+		{"far past the end", []string{"a"}, 10, "a"},
+		{"negative", []string{"a", "b"}, -1, "a|b"},
+		{"empty", nil, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rows []*sheets.RowData
+			for _, value := range tt.rows {
+				rows = append(rows, textRow(value))
+			}
+
+			if got := gridValues(RemoveRow(rows, tt.index)); got != tt.want {
+				t.Errorf("RemoveRow(%v, %d) = %q, want %q", tt.rows, tt.index, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildSectionedRows(t *testing.T) {
+	header := NewStyler().FontBold(true)
+	body := NewStyler()
+
+	groups := map[string][][]interface{}{
+		"West": {{"Carol", 30.5}},
+		"East": {{"Alice", 10}, {"Bob", 20, true}},
+	}
+
+	rows, merges := BuildSectionedRows(groups, header, body, nil)
+
+	// East sorts before West, and each group starts with its title row.
+	if got := gridValues(rows); got != "East,,|Alice,10|Bob,20,true|West,,|Carol,30.5" {
+		t.Errorf("rows = %s", got)
+	}
+
+	for _, i := range []int{0, 3} {
+		for _, cell := range rows[i].Values {
+			if !cell.UserEnteredFormat.TextFormat.Bold {
+				t.Errorf("title row %d has a cell without the header format", i)
+			}
+		}
+	}
+	if rows[1].Values[0].UserEnteredFormat.TextFormat.Bold {
+		t.Error("body row has the header format")
+	}
+	if v := rows[1].Values[1].UserEnteredValue; v.NumberValue == nil || *v.NumberValue != 10 {
+		t.Errorf("int value = %+v, want a number", v)
+	}
+	if v := rows[2].Values[2].UserEnteredValue; v.BoolValue == nil || !*v.BoolValue {
+		t.Errorf("bool value = %+v, want a boolean", v)
+	}
+
+	if len(merges) != 2 {
+		t.Fatalf("got %d merges, want 2", len(merges))
+	}
+	for i, row := range []int64{0, 3} {
+		m := merges[i].MergeCells
+		if m.MergeType != "MERGE_ALL" || gridRangeToA1("", m.Range) != fmt.Sprintf("A%d:C%d", row+1, row+1) {
+			t.Errorf("merge %d = %+v, want row %d across A:C", i, m.Range, row+1)
+		}
+	}
+}
+
+func TestBuildSectionedRowsEmpty(t *testing.T) {
+	rows, merges := BuildSectionedRows(nil, NewStyler(), NewStyler(), nil)
+	if len(rows) != 0 || len(merges) != 0 {
+		t.Errorf("got %d rows and %d merges, want none", len(rows), len(merges))
+	}
+}
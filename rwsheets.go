@@ -1,31 +1,312 @@
 package rwsheets
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"google.golang.org/api/googleapi"
 	sheets "google.golang.org/api/sheets/v4"
 )
 
+// cellFields is the fields mask covering everything a user can set on a cell.
+const cellFields = "userEnteredValue,userEnteredFormat,note,dataValidation,textFormatRuns"
+
 var (
-	ErrNoData = errors.New("no sheet data found")
+	ErrNoData         = errors.New("no sheet data found")
+	ErrPageSize       = errors.New("page size must be at least 1")
+	ErrDataSourceCell = errors.New("data source cells need a formula starting with = that references a data source column")
 )
 
 // GetSheetData: Retrieve the spreadsheet data for one sheet.
 func GetSheetData(ssid, readRange string, srv *sheets.Service) ([]*sheets.RowData, error) {
 	var rows []*sheets.RowData
+
+	grid, err := getGridData(ssid, readRange, srv)
+	if err != nil {
+		return rows, err
+	}
+
+	rows = grid.RowData
+	return rows, nil
+}
+
+// GetSheetDataRich: Retrieve the spreadsheet data for one sheet, requesting only the given cell fields.
+// fields is used as the mask for each cell, so "userEnteredValue,effectiveValue,userEnteredFormat.numberFormat"
+// returns the typed values and number formats and nothing else, which is much smaller and faster to read
+// than the full grid data GetSheetData returns. An empty mask returns every cell field.
+//
+// Some useful masks are:
+//   - "effectiveValue" for the computed values, typed as numbers, strings or bools.
+//   - "formattedValue" for the values as displayed in the sheet.
+//   - "userEnteredValue" for the values as entered, including formulas.
+//   - "effectiveValue,effectiveFormat.numberFormat" for values and how they are displayed, to render them elsewhere.
+//   - "userEnteredValue,userEnteredFormat,note,dataValidation,textFormatRuns" for everything needed to copy the cells.
+func GetSheetDataRich(ssid, readRange string, fields string, srv *sheets.Service) ([]*sheets.RowData, error) {
+	var rows []*sheets.RowData
+
+	if fields == "" {
+		fields = "*"
+	}
+
+	ss, err := srv.Spreadsheets.Get(ssid).Ranges(readRange).
+		Fields(googleapi.Field("sheets(data(startRow,startColumn,rowData(values(" + fields + "))))")).Do()
+	if err != nil {
+		return rows, err
+	}
+
+	grid, err := firstGrid(ss)
+	if err != nil {
+		return rows, err
+	}
+
+	rows = grid.RowData
+	return rows, nil
+}
+
+// ReadOptions: struct to be used to change how GetSheetDataWithOptions reads the sheet data.
+type ReadOptions struct {
+	// PadToWidth pads every row with empty cells to the full width of the range, so columns stay aligned
+	// even when rows end in empty cells. If the range doesn't have an end column, the widest row is used.
+	PadToWidth bool
+}
+
+// GetSheetDataWithOptions: Retrieve the spreadsheet data for one sheet using the given read options.
+func GetSheetDataWithOptions(ssid, readRange string, opts ReadOptions, srv *sheets.Service) ([]*sheets.RowData, error) {
+	var rows []*sheets.RowData
+
+	grid, err := getGridData(ssid, readRange, srv)
+	if err != nil {
+		return rows, err
+	}
+	rows = grid.RowData
+
+	if opts.PadToWidth {
+		width := 0
+		if _, gr, err := parseA1(readRange); err == nil && gr.EndColumnIndex > 0 {
+			width = int(gr.EndColumnIndex - gr.StartColumnIndex)
+		}
+		rows = PadRows(rows, width)
+	}
+
+	return rows, nil
+}
+
+// GetSheetDataIndexed: Retrieve the spreadsheet data for one sheet as a map of A1 cell references to values.
+// Empty cells are left out of the map.
+func GetSheetDataIndexed(ssid, readRange string, srv *sheets.Service) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	grid, err := getGridData(ssid, readRange, srv)
+	if err != nil {
+		return values, err
+	}
+
+	for i, row := range grid.RowData {
+		for j, cell := range row.Values {
+			value := cellValue(cell)
+			if value == nil {
+				continue
+			}
+
+			ref := CellRef(grid.StartRow+int64(i), grid.StartColumn+int64(j))
+			values[ref] = value
+		}
+	}
+
+	return values, nil
+}
+
+// FindRowByKey: Finds the first row in the range whose key column matches the key.
+// Returns the zero-based index of the row in the sheet along with the row, or ErrNoData if no row matches.
+// keyColumnIndex is relative to the first column of the range.
+func FindRowByKey(ssid, readRange string, key string, keyColumnIndex int, srv *sheets.Service) (int, *sheets.RowData, error) {
+	grid, err := getGridData(ssid, readRange, srv)
+	if err != nil {
+		return -1, nil, err
+	}
+
+	i := findRowByKey(grid.RowData, key, keyColumnIndex)
+	if i < 0 {
+		return -1, nil, ErrNoData
+	}
+	return int(grid.StartRow) + i, grid.RowData[i], nil
+}
+
+// GetSheetDataPaged: Retrieve the spreadsheet data for one sheet, reading rowPageSize rows at a time.
+// The pages are stitched together in order, so the result matches what GetSheetData would return
+// for ranges that are too large to read in a single request.
+func GetSheetDataPaged(ssid, readRange string, rowPageSize int, srv *sheets.Service) ([]*sheets.RowData, error) {
+	return GetSheetDataPagedContext(context.Background(), ssid, readRange, rowPageSize, srv)
+}
+
+// GetSheetDataPagedContext: Same as GetSheetDataPaged, but stops reading and returns the contexts
+// error as soon as the context is cancelled.
+func GetSheetDataPagedContext(ctx context.Context, ssid, readRange string, rowPageSize int, srv *sheets.Service) ([]*sheets.RowData, error) {
+	var rows []*sheets.RowData
+
+	err := pageSheetData(ctx, ssid, readRange, rowPageSize, srv, func(startRow int64, page []*sheets.RowData) error {
+		rows = append(rows, page...)
+		return nil
+	})
+	if err != nil {
+		return rows, err
+	}
+
+	// Drop the empty rows the last pages were padded with.
+	for len(rows) > 0 && len(rows[len(rows)-1].Values) == 0 {
+		rows = rows[:len(rows)-1]
+	}
+	return rows, nil
+}
+
+// ForEachRow: Reads the range rowPageSize rows at a time, calling fn for each row in order.
+// rowIndex is the zero-based index of the row in the sheet, and empty rows are passed as empty RowData.
+// Only one page of rows is held in memory at a time. Reading stops as soon as fn returns an error
+// or the context is cancelled, and that error is returned.
+func ForEachRow(ctx context.Context, ssid, readRange string, rowPageSize int, fn func(rowIndex int, row *sheets.RowData) error, srv *sheets.Service) error {
+	return pageSheetData(ctx, ssid, readRange, rowPageSize, srv, func(startRow int64, page []*sheets.RowData) error {
+		for i, row := range page {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := fn(int(startRow)+i, row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetRowsSince: Retrieve the rows of the sheet from the zero-based fromRow to the last row with data,
+// along with the index after that row to pass as fromRow next time. Useful for tailing a sheet that
+// is being appended to, reading only the rows added since the last call.
+// If there are no new rows, no rows are returned along with the same fromRow.
+func GetRowsSince(ssid, sheetTitle string, fromRow int64, srv *sheets.Service) ([]*sheets.RowData, int64, error) {
+	if fromRow < 0 {
+		return nil, fromRow, fmt.Errorf("%w: row %d", ErrInvalidRange, fromRow)
+	}
+
+	rowCount, err := sheetRowCount(context.Background(), ssid, sheetTitle, srv)
+	if err != nil {
+		return nil, fromRow, err
+	}
+	if fromRow >= rowCount {
+		return nil, fromRow, nil
+	}
+
+	// A1 rows are one-based and inclusive.
+	grid, err := getGridData(ssid, BuildRange(sheetTitle, fmt.Sprintf("%d:%d", fromRow+1, rowCount)), srv)
+	if errors.Is(err, ErrNoData) {
+		return nil, fromRow, nil
+	} else if err != nil {
+		return nil, fromRow, err
+	}
+
+	// The API leaves off trailing empty rows, but make sure none are counted as new.
+	rows := grid.RowData
+	for len(rows) > 0 && rowWidth(rows[len(rows)-1]) == 0 {
+		rows = rows[:len(rows)-1]
+	}
+	return rows, fromRow + int64(len(rows)), nil
+}
+
+// pageSheetData: Reads the range in windows of rowPageSize rows, calling fn with each window in order.
+// Each window is padded with empty rows to its full size so rows stay aligned across windows.
+// If the range doesn't have an end row, the sheets row count is used instead.
+func pageSheetData(ctx context.Context, ssid, readRange string, rowPageSize int, srv *sheets.Service, fn func(startRow int64, rows []*sheets.RowData) error) error {
+	if rowPageSize < 1 {
+		return ErrPageSize
+	}
+
+	title, gr, err := parseA1(readRange)
+	if err != nil {
+		return err
+	}
+
+	endRow := gr.EndRowIndex
+	if endRow == 0 {
+		if endRow, err = sheetRowCount(ctx, ssid, title, srv); err != nil {
+			return err
+		}
+	}
+
+	pageSize := int64(rowPageSize)
+	for start := gr.StartRowIndex; start < endRow; start += pageSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		window := *gr
+		window.StartRowIndex = start
+		window.EndRowIndex = min(start+pageSize, endRow)
+
+		ss, err := srv.Spreadsheets.Get(ssid).Ranges(gridRangeToA1(title, &window)).IncludeGridData(true).Context(ctx).Do()
+		if err != nil {
+			return err
+		}
+
+		grid, err := firstGrid(ss)
+		if err != nil {
+			return err
+		}
+
+		rows := grid.RowData
+		for int64(len(rows)) < window.EndRowIndex-start {
+			rows = append(rows, &sheets.RowData{})
+		}
+
+		if err := fn(start, rows); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sheetRowCount: Retrieve the number of rows in the sheet with the given title, or the first sheet if the title is empty.
+func sheetRowCount(ctx context.Context, ssid, title string, srv *sheets.Service) (int64, error) {
+	call := srv.Spreadsheets.Get(ssid).Fields("sheets.properties.gridProperties.rowCount").Context(ctx)
+	if title != "" {
+		call = call.Ranges(gridRangeToA1(title, nil))
+	}
+
+	ss, err := call.Do()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(ss.Sheets) == 0 || ss.Sheets[0].Properties == nil || ss.Sheets[0].Properties.GridProperties == nil {
+		return 0, ErrNoData
+	}
+	return ss.Sheets[0].Properties.GridProperties.RowCount, nil
+}
+
+// getGridData: Retrieve the grid data for the first sheet in the read range.
+func getGridData(ssid, readRange string, srv *sheets.Service) (*sheets.GridData, error) {
 	var ranges []string
 	ranges = append(ranges, readRange)
 
 	// Get the spreadsheet data.
 	ss, err := srv.Spreadsheets.Get(ssid).Ranges(ranges...).IncludeGridData(true).Do()
 	if err != nil {
-		return rows, err
+		return nil, err
 	}
 
+	return firstGrid(ss)
+}
+
+// firstGrid: Extracts the grid data of the first sheet in the spreadsheet.
+func firstGrid(ss *sheets.Spreadsheet) (*sheets.GridData, error) {
 	// Make sure we actually got at least one sheet of data.
 	if len(ss.Sheets) == 0 {
-		return rows, ErrNoData
+		return nil, ErrNoData
 	}
 
 	// Extract the row data from the sheet.
@@ -33,13 +314,10 @@ func GetSheetData(ssid, readRange string, srv *sheets.Service) ([]*sheets.RowDat
 
 	// Make sure we actually have data.
 	if len(sheet.Data) == 0 {
-		return rows, ErrNoData
+		return nil, ErrNoData
 	}
 
-	grid := sheet.Data[0]
-	rows = grid.RowData
-
-	return rows, nil
+	return sheet.Data[0], nil
 }
 
 // UpdateSheetData: Update the spreadsheet with new values.
@@ -47,6 +325,66 @@ func UpdateSheetData(ssid string, endColumnIndex, gid, startColumnIndex, startRo
 	var batchUpdate sheets.BatchUpdateSpreadsheetRequest
 	batchUpdate.IncludeSpreadsheetInResponse = false
 
+	request := updateCellsRequest(endColumnIndex, gid, startColumnIndex, startRowIndex, newVals, "*")
+	batchUpdate.Requests = append(batchUpdate.Requests, request)
+	batchUpdate.MarshalJSON()
+
+	if _, err := srv.Spreadsheets.BatchUpdate(ssid, &batchUpdate).Do(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AppendRows: Appends the rows after the last row with data on the sheet of the A1 range, keeping any
+// formatting carried in the cells. Only the sheet and start column of the range are used, so "Log" and
+// "Log!A:D" both append to the Log sheet, while "Log!C:D" appends the rows starting in column C.
+// If the range doesn't name a sheet, the rows are appended to the first sheet.
+func AppendRows(ssid, a1Range string, rows []*sheets.RowData, srv *sheets.Service) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	title, gr, err := parseA1(a1Range)
+	if err != nil {
+		return err
+	}
+
+	gid, err := sheetID(ssid, title, srv)
+	if err != nil {
+		return err
+	}
+
+	// AppendCells always starts at the first column, so shift the rows over with empty cells.
+	if gr.StartColumnIndex > 0 {
+		shifted := make([]*sheets.RowData, len(rows))
+		for i, row := range rows {
+			cells := make([]*sheets.CellData, gr.StartColumnIndex)
+			for j := range cells {
+				cells[j] = &sheets.CellData{}
+			}
+			if row != nil {
+				cells = append(cells, row.Values...)
+			}
+			shifted[i] = &sheets.RowData{Values: cells}
+		}
+		rows = shifted
+	}
+
+	request := sheets.Request{
+		AppendCells: &sheets.AppendCellsRequest{
+			Fields:  "*",
+			Rows:    rows,
+			SheetId: gid,
+		},
+	}
+
+	_, err = batchUpdate(ssid, []*sheets.Request{&request}, srv)
+	return err
+}
+
+// updateCellsRequest: Creates the request for updating the given fields of the cells starting at the given indices.
+func updateCellsRequest(endColumnIndex, gid, startColumnIndex, startRowIndex int64, newVals []*sheets.RowData, fields string) *sheets.Request {
 	gridRange := sheets.GridRange{
 		EndColumnIndex:   endColumnIndex,
 		SheetId:          gid,
@@ -56,32 +394,104 @@ func UpdateSheetData(ssid string, endColumnIndex, gid, startColumnIndex, startRo
 	}
 
 	updateCells := sheets.UpdateCellsRequest{
-		Fields: "*",
+		Fields: fields,
 		Range:  &gridRange,
 		Rows:   newVals,
 	}
 
-	request := sheets.Request{
+	return &sheets.Request{
 		UpdateCells: &updateCells,
 	}
-	batchUpdate.Requests = append(batchUpdate.Requests, &request)
-	batchUpdate.MarshalJSON()
+}
 
-	if _, err := srv.Spreadsheets.BatchUpdate(ssid, &batchUpdate).Do(); err != nil {
+// Upsert: Overwrites the first row in the sheet whose key column matches the key, or appends the row
+// after the last row with data if none match.
+//
+// The sheet is read and then written in two separate calls, so a write made by someone else in between
+// can be overwritten, or cause the same key to be appended twice.
+func Upsert(ssid string, gid int64, keyColumnIndex int, key string, row *sheets.RowData, srv *sheets.Service) error {
+	title, err := sheetTitle(ssid, gid, srv)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	var rows []*sheets.RowData
+	grid, err := getGridData(ssid, QuoteSheetName(title), srv)
+	if err == nil {
+		rows = grid.RowData
+	} else if !errors.Is(err, ErrNoData) {
+		return err
+	}
+
+	var request *sheets.Request
+	if i := findRowByKey(rows, key, keyColumnIndex); i >= 0 {
+		request = updateCellsRequest(int64(len(row.Values)), gid, 0, int64(i), []*sheets.RowData{row}, "*")
+	} else {
+		request = &sheets.Request{
+			AppendCells: &sheets.AppendCellsRequest{
+				Fields:  "*",
+				Rows:    []*sheets.RowData{row},
+				SheetId: gid,
+			},
+		}
+	}
+
+	_, err = batchUpdate(ssid, []*sheets.Request{request}, srv)
+	return err
 }
 
-// RemoveRow: For removing a specific row in a Sheet.
-func RemoveRow(rows []*sheets.RowData, rmvIdx int) []*sheets.RowData {
-	if len(rows) == rmvIdx {
-		// Let's assume we wanted to remove the last row ..
-		return rows[:rmvIdx-2]
+// MapCells: Applies fn to every cell in the range and writes back only the cells that changed.
+// fn is given a copy of each cell, and returning nil leaves the cell unchanged.
+// gid should be the ID of the sheet the range is on.
+func MapCells(ssid, readRange string, gid int64, fn func(*sheets.CellData) *sheets.CellData, srv *sheets.Service) error {
+	grid, err := getGridData(ssid, readRange, srv)
+	if err != nil {
+		return err
 	}
 
-	if rmvIdx+1 > len(rows) {
+	var mapped []*sheets.RowData
+	for _, row := range grid.RowData {
+		var cells []*sheets.CellData
+		for _, cell := range row.Values {
+			if newCell := fn(cloneCell(cell)); newCell != nil {
+				cell = newCell
+			}
+			cells = append(cells, cell)
+		}
+		mapped = append(mapped, &sheets.RowData{Values: cells})
+	}
+
+	var requests []*sheets.Request
+	for _, change := range DiffRows(grid.RowData, mapped) {
+		startRow := grid.StartRow + int64(change.Row)
+		startCol := grid.StartColumn + int64(change.Col)
+		rows := []*sheets.RowData{{Values: []*sheets.CellData{change.Cell}}}
+		requests = append(requests, updateCellsRequest(startCol+1, gid, startCol, startRow, rows, cellFields))
+	}
+
+	if len(requests) == 0 {
+		return nil
+	}
+
+	_, err = batchUpdate(ssid, requests, srv)
+	return err
+}
+
+// batchUpdate: Sends the given requests to the spreadsheet in a single batch update.
+func batchUpdate(ssid string, requests []*sheets.Request, srv *sheets.Service) (*sheets.BatchUpdateSpreadsheetResponse, error) {
+	update := sheets.BatchUpdateSpreadsheetRequest{
+		IncludeSpreadsheetInResponse: false,
+		Requests:                     requests,
+	}
+
+	return srv.Spreadsheets.BatchUpdate(ssid, &update).Do()
+}
+
+// RemoveRow: For removing a specific row in a Sheet.
+// Returns the rows without the row at the zero-based index, or the rows unchanged if the index is out of range.
+// The rows are only removed locally, use DeleteRows to remove them from the sheet.
+func RemoveRow(rows []*sheets.RowData, rmvIdx int) []*sheets.RowData {
+	if rmvIdx < 0 || rmvIdx >= len(rows) {
 		return rows
 	}
 
@@ -96,6 +506,7 @@ func BoolValue(value bool) *sheets.ExtendedValue {
 }
 
 // FormulaValue: For updating UserEnteredValue with a formula value.
+// Use CheckFormula or the stylers FormulaCell to catch formulas that are too long for Sheets.
 func FormulaValue(value string) *sheets.ExtendedValue {
 	return &sheets.ExtendedValue{
 		FormulaValue: &value,
@@ -117,22 +528,63 @@ func NumberValue(value float64) *sheets.ExtendedValue {
 	}
 }
 
-// SerialDate: Returns the Google Sheets serial number for the date.
+// SerialDate: Returns the Google Sheets serial number for the date, the number of days since the 1899-12-30 epoch.
+// The time of day is kept as the fractional part, so "2024-03-10 12:00" is 45361.5.
+// The value is parsed as UTC, or in the offset given by the layout, and the wall clock time is used as written,
+// so daylight saving changes and offsets never move the date or time shown in the sheet.
+// Use SerialDateInLocation to convert values with an offset to the wall clock of another location.
 func SerialDate(value, format string) (float64, error) {
 	newDate, err := time.Parse(format, value)
 	if err != nil {
 		return float64(0.0), err
 	}
 
-	startDate, err := time.Parse("1/2/2006", "12/30/1899")
+	return timeToSerial(newDate), nil
+}
+
+// SerialDateInLocation: Returns the Google Sheets serial number for the date, as shown on a wall clock in the location.
+// Values without a zone in the layout are parsed in the location, and values with one, like "2024-03-10T07:30:00Z",
+// are converted to the location, so UTC timestamps of west coast data land on the day they happened there.
+// A nil location is treated as UTC.
+func SerialDateInLocation(value, format string, loc *time.Location) (float64, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	newDate, err := time.ParseInLocation(format, value, loc)
 	if err != nil {
 		return float64(0.0), err
 	}
 
-	days := newDate.Sub(startDate).Hours() / 24
-	return float64(days), nil
+	return timeToSerial(newDate.In(loc)), nil
 }
 
+// timeToSerial: Returns the Google Sheets serial number for the time, using its wall clock in its own location.
+// The days are counted with Unix seconds rather than a Duration, which would overflow for dates
+// more than 292 years from the epoch.
+func timeToSerial(t time.Time) float64 {
+	epoch := time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+
+	seconds := wall.Unix() - epoch.Unix()
+	return float64(seconds)/secondsPerDay + float64(wall.Nanosecond())/(secondsPerDay*1e9)
+}
+
+// SerialToTime: Returns the time in UTC for the Google Sheets serial number, reversing SerialDate.
+// The whole days are added to the 1899-12-30 epoch, so negative serials are dates before it,
+// and the fractional part is the time of day rounded to the millisecond, the precision Sheets keeps.
+// This removes the floating point noise in serials read back from a sheet, so 45000.5 is exactly noon.
+func SerialToTime(serial float64) time.Time {
+	epoch := time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+	days := math.Floor(serial)
+	millis := math.Round((serial - days) * secondsPerDay * 1000)
+	return epoch.AddDate(0, 0, int(days)).Add(time.Duration(millis) * time.Millisecond)
+}
+
+// secondsPerDay is the length of a day in a serial number, which never has daylight saving changes.
+const secondsPerDay = 24 * 60 * 60
+
 // TextFormat: Provides a new sheets text format.
 func TextFormat(fontFamily string, fontSize int64) *sheets.TextFormat {
 	return &sheets.TextFormat{
@@ -164,7 +616,8 @@ func CurrencyFormat() *sheets.NumberFormat {
 	}
 }
 
-// Color: Creates a new Sheets ColorStyle with the given ARGB values.
+// Color: Creates a new Sheets ColorStyle with the given alpha, blue, green and red values, in that order.
+// Kept for compatibility; use ColorRGB, ColorRGBA or ColorHex instead, which take the components in the usual order.
 func Color(a, b, g, r float64) *sheets.ColorStyle {
 	color := sheets.Color{
 		Alpha: a,
@@ -178,7 +631,10 @@ func Color(a, b, g, r float64) *sheets.ColorStyle {
 }
 
 // BorderConf: struct to be used to set the border style configuartion.
-// BorderConf currently only supports one color for all sides that are set to true.
+// Color and Style are used for every side that is set to true, unless the side has its own color or style set.
+//
+// Diagonal borders can't be set: the Sheets API v4 Borders and CellFormat only have the four sides,
+// so there is no field to send them in. Use Styler.Strikethrough to mark a cell as struck out instead.
 type BorderConf struct {
 	Bottom bool
 	Left   bool
@@ -186,6 +642,16 @@ type BorderConf struct {
 	Style  string
 	Top    bool
 	Color  *sheets.ColorStyle // Optional. Color will be set to black if not set.
+
+	// Optional. Per side colors and styles, used instead of Color and Style for that side when set.
+	BottomColor *sheets.ColorStyle
+	LeftColor   *sheets.ColorStyle
+	RightColor  *sheets.ColorStyle
+	TopColor    *sheets.ColorStyle
+	BottomStyle string
+	LeftStyle   string
+	RightStyle  string
+	TopStyle    string
 }
 
 var RIGHT_BORDER = &BorderConf{
@@ -222,42 +688,94 @@ func CellBorders(conf *BorderConf) *sheets.Borders {
 	if color == nil {
 		color = BLACK_COLOR
 	}
-	if conf.Bottom {
-		borders.Bottom = &sheets.Border{
-			ColorStyle: color,
-			Style:      style,
+
+	// side: Creates the border for one side, falling back to the shared color and style.
+	side := func(sideColor *sheets.ColorStyle, sideStyle string) *sheets.Border {
+		if sideColor == nil {
+			sideColor = color
 		}
+		if sideStyle == "" {
+			sideStyle = style
+		}
+		return &sheets.Border{
+			ColorStyle: sideColor,
+			Style:      sideStyle,
+		}
+	}
+
+	if conf.Bottom {
+		borders.Bottom = side(conf.BottomColor, conf.BottomStyle)
 	}
 	if conf.Left {
-		borders.Left = &sheets.Border{
-			ColorStyle: color,
-			Style:      style,
-		}
+		borders.Left = side(conf.LeftColor, conf.LeftStyle)
 	}
 	if conf.Right {
-		borders.Right = &sheets.Border{
-			ColorStyle: color,
-			Style:      style,
-		}
+		borders.Right = side(conf.RightColor, conf.RightStyle)
 	}
 	if conf.Top {
-		borders.Top = &sheets.Border{
-			ColorStyle: color,
-			Style:      style,
-		}
+		borders.Top = side(conf.TopColor, conf.TopStyle)
 	}
 	return &borders
 }
 
+const (
+	// NegativeParens renders negative numbers wrapped in parentheses.
+	NegativeParens = "PARENS"
+
+	// NegativeMinus renders negative numbers with a leading minus sign.
+	NegativeMinus = "MINUS"
+
+	// NegativeRed renders negative numbers in red with a leading minus sign.
+	NegativeRed = "RED"
+)
+
+// negativePattern: Replaces the negative section of a number pattern based on the given style.
+// The positive section is kept as is, and any zero or text sections are carried over.
+func negativePattern(pattern, style string) string {
+	sections := strings.Split(pattern, ";")
+	positive := sections[0]
+	base := strings.ReplaceAll(positive, "_)", "")
+
+	var negative string
+	switch style {
+	case NegativeParens:
+		negative = "(" + base + ")"
+	case NegativeMinus:
+		negative = "-" + base
+	case NegativeRed:
+		negative = "[Red]-" + base
+	default:
+		return pattern
+	}
+
+	result := []string{positive, negative}
+	if len(sections) > 2 {
+		result = append(result, sections[2:]...)
+	}
+	return strings.Join(result, ";")
+}
+
 // Styler is to be used to create new cells with styling.
 type Styler struct {
 	fontBold            bool
+	italic              bool
+	underline           bool
+	strikethrough       bool
 	fontFamily          string
 	fontSize            int64
 	datePattern         string
 	numberPattern       string
+	decimals            int
+	percentPattern      string
 	horizontalAlignment string
 	verticalAlignment   string
+	negativeStyle       string
+	currencySymbol      string
+	textDirection       string
+	wrapStrategy        string
+	textRotation        *sheets.TextRotation
+	backgroundColor     *sheets.ColorStyle
+	textColor           *sheets.ColorStyle
 }
 
 // NewStyler: Returns a new styler with developer preferred settings.
@@ -268,7 +786,8 @@ func NewStyler() *Styler {
 		fontFamily:          "Verdana",
 		fontSize:            int64(10),
 		datePattern:         "M/d/yyyy",
-		numberPattern:       "#,##0.00_);-#,##0.00",
+		decimals:            2,
+		percentPattern:      "0.00%",
 		horizontalAlignment: "LEFT",
 		verticalAlignment:   "MIDDLE",
 	}
@@ -280,6 +799,24 @@ func (s *Styler) FontBold(bold bool) *Styler {
 	return s
 }
 
+// Sets whether the styler should make the font italic.
+func (s *Styler) Italic(italic bool) *Styler {
+	s.italic = italic
+	return s
+}
+
+// Sets whether the styler should underline the text.
+func (s *Styler) Underline(underline bool) *Styler {
+	s.underline = underline
+	return s
+}
+
+// Sets whether the styler should strike through the text, like for cancelled line items.
+func (s *Styler) Strikethrough(strikethrough bool) *Styler {
+	s.strikethrough = strikethrough
+	return s
+}
+
 // Sets the stylers default font family.
 func (s *Styler) FontFamily(fontFamily string) *Styler {
 	s.fontFamily = fontFamily
@@ -299,11 +836,66 @@ func (s *Styler) DatePattern(pattern string) *Styler {
 }
 
 // Sets the stylers number pattern to use when creating number value cells.
+// The pattern is used instead of the one built from the stylers decimals, and an empty pattern goes back to it.
 func (s *Styler) NumberPattern(pattern string) *Styler {
 	s.numberPattern = pattern
 	return s
 }
 
+// Sets the number of decimal places the styler shows in number value cells, building the number pattern for it,
+// like "#,##0.000_);-#,##0.000" for 3. Numbers are shown without a decimal point for 0, and decimals below 0 are treated as 0.
+// An explicit pattern set with NumberPattern takes precedence over the decimals.
+func (s *Styler) Decimals(n int) *Styler {
+	s.decimals = max(n, 0)
+	return s
+}
+
+// decimalsPattern: Returns the number pattern showing n decimal places, the default number pattern for 2.
+func decimalsPattern(n int) string {
+	number := "#,##0"
+	if n > 0 {
+		number += "." + strings.Repeat("0", n)
+	}
+	return number + "_);-" + number
+}
+
+// Sets the stylers percent pattern to use when creating percent value cells.
+func (s *Styler) PercentPattern(pattern string) *Styler {
+	s.percentPattern = pattern
+	return s
+}
+
+// Sets how the styler renders negative numbers in number, currency and accounting formats.
+// Accepts "PARENS", "MINUS" or "RED"; any other value leaves the styler unchanged.
+// The style is applied when the formats are built, so it works with any number pattern.
+func (s *Styler) NegativeStyle(style string) *Styler {
+	style = strings.ToUpper(style)
+	switch style {
+	case NegativeParens, NegativeMinus, NegativeRed:
+		s.negativeStyle = style
+	}
+	return s
+}
+
+// Sets the currency symbol the styler uses in currency and accounting formats, like "€", "£" or "¥".
+// The symbol is placed before the number and an empty symbol goes back to "$". The grouping and decimal
+// separators follow the spreadsheets locale, so set the locale to match, like de_DE for "1.234,56".
+// For other placements, use FormattedNumberCell with a pattern like `#,##0.00 "€"`.
+func (s *Styler) CurrencySymbol(symbol string) *Styler {
+	s.currencySymbol = symbol
+	return s
+}
+
+// currencyLiteral: Returns the stylers currency symbol quoted for use in a number pattern.
+// Double quotes can't be escaped inside a quoted literal, so they are removed from the symbol.
+func (s *Styler) currencyLiteral() string {
+	symbol := s.currencySymbol
+	if symbol == "" {
+		symbol = "$"
+	}
+	return `"` + strings.ReplaceAll(symbol, `"`, "") + `"`
+}
+
 // Sets the stylers horizontal alignment to use when creating text formats.
 func (s *Styler) HorizontalAlignment(alignment string) *Styler {
 	if alignment == "" {
@@ -325,12 +917,112 @@ func (s *Styler) VerticalAlignment(alignment string) *Styler {
 	return s
 }
 
+// Sets the stylers text direction to use when creating cells.
+// Accepts "LEFT_TO_RIGHT" or "RIGHT_TO_LEFT"; any other value leaves the styler unchanged.
+func (s *Styler) TextDirection(direction string) *Styler {
+	switch direction {
+	case "LEFT_TO_RIGHT", "RIGHT_TO_LEFT":
+		s.textDirection = direction
+	}
+	return s
+}
+
+// Sets the stylers background color to use when creating cells, like ColorHex("#F3F3F3") for row shading.
+// A nil color leaves the background unset.
+func (s *Styler) BackgroundColor(c *sheets.ColorStyle) *Styler {
+	s.backgroundColor = c
+	return s
+}
+
+// Sets the stylers text color to use when creating text formats, like ColorHex("#CC0000") for negative variances.
+// A nil color leaves the text color unset.
+func (s *Styler) TextColor(c *sheets.ColorStyle) *Styler {
+	s.textColor = c
+	return s
+}
+
+// Clone: Returns a copy of the styler, so its settings can be changed without affecting the original.
+func (s *Styler) Clone() *Styler {
+	c := *s
+	return &c
+}
+
+// WithAlignment: Returns a copy of the styler using the horizontal alignment, leaving the styler unchanged.
+// Useful when one styler is shared across columns, like styler.WithAlignment("RIGHT").NumberCell(...).
+func (s *Styler) WithAlignment(alignment string) *Styler {
+	return s.Clone().HorizontalAlignment(alignment)
+}
+
+// WithVerticalAlignment: Returns a copy of the styler using the vertical alignment, leaving the styler unchanged.
+func (s *Styler) WithVerticalAlignment(alignment string) *Styler {
+	return s.Clone().VerticalAlignment(alignment)
+}
+
+// WithFontBold: Returns a copy of the styler with the font bold setting, leaving the styler unchanged.
+func (s *Styler) WithFontBold(bold bool) *Styler {
+	return s.Clone().FontBold(bold)
+}
+
+// WithFontSize: Returns a copy of the styler using the font size, leaving the styler unchanged.
+func (s *Styler) WithFontSize(fontSize int64) *Styler {
+	return s.Clone().FontSize(fontSize)
+}
+
+// WithDatePattern: Returns a copy of the styler using the date pattern, leaving the styler unchanged.
+func (s *Styler) WithDatePattern(pattern string) *Styler {
+	return s.Clone().DatePattern(pattern)
+}
+
+// WithNumberPattern: Returns a copy of the styler using the number pattern, leaving the styler unchanged.
+func (s *Styler) WithNumberPattern(pattern string) *Styler {
+	return s.Clone().NumberPattern(pattern)
+}
+
+// Sets the stylers wrap strategy to use when creating cells, like "WRAP" for multi-line notes.
+// Accepts "OVERFLOW_CELL", "LEGACY_WRAP", "CLIP" or "WRAP"; any other value leaves the styler unchanged.
+func (s *Styler) WrapStrategy(strategy string) *Styler {
+	switch strategy {
+	case "OVERFLOW_CELL", "LEGACY_WRAP", "CLIP", "WRAP":
+		s.wrapStrategy = strategy
+	}
+	return s
+}
+
+// Sets the angle, in degrees, the styler rotates text by when creating cells, like 45 or 90 for narrow headers.
+// Positive angles rotate counterclockwise. Angles outside of -90 to 90, which the API rejects, leave the styler unchanged.
+func (s *Styler) TextRotationAngle(angle int64) *Styler {
+	if angle < -90 || angle > 90 {
+		return s
+	}
+
+	s.textRotation = &sheets.TextRotation{
+		Angle: angle,
+		// An angle of 0 would otherwise be dropped, sending a rotation the API rejects.
+		ForceSendFields: []string{"Angle"},
+	}
+	return s
+}
+
+// Sets whether the styler stacks text vertically, one character per line, when creating cells.
+// Replaces any rotation angle, and false removes the rotation.
+func (s *Styler) TextRotationVertical(vertical bool) *Styler {
+	s.textRotation = nil
+	if vertical {
+		s.textRotation = &sheets.TextRotation{Vertical: true}
+	}
+	return s
+}
+
 // TextFormat: Provides a new sheets Text Format using the stylers settings.
 func (s *Styler) TextFormat() *sheets.TextFormat {
 	return &sheets.TextFormat{
-		Bold:       s.fontBold,
-		FontFamily: s.fontFamily,
-		FontSize:   s.fontSize,
+		Bold:                 s.fontBold,
+		FontFamily:           s.fontFamily,
+		FontSize:             s.fontSize,
+		ForegroundColorStyle: s.textColor,
+		Italic:               s.italic,
+		Strikethrough:        s.strikethrough,
+		Underline:            s.underline,
 	}
 }
 
@@ -343,119 +1035,240 @@ func (s *Styler) DateFormat() *sheets.NumberFormat {
 }
 
 // NumberFormat: Provides a sheets number format for a number value using the stylers settings.
+// If a negative style has been set, it replaces the negative section of the number pattern.
 func (s *Styler) NumberFormat() *sheets.NumberFormat {
+	pattern := s.numberPattern
+	if pattern == "" {
+		pattern = decimalsPattern(s.decimals)
+	}
+
 	return &sheets.NumberFormat{
-		Pattern: s.numberPattern,
+		Pattern: negativePattern(pattern, s.negativeStyle),
 		Type:    "NUMBER",
 	}
 }
 
+// PercentFormat: Provides a sheets number format for a percent value using the stylers settings.
+func (s *Styler) PercentFormat() *sheets.NumberFormat {
+	return &sheets.NumberFormat{
+		Pattern: s.percentPattern,
+		Type:    "PERCENT",
+	}
+}
+
 // CurrencyFormat: Provides the default currency formatting using the styler.
+// If a negative style has been set, the currency pattern will use it for negative values.
+// Without a negative style or currency symbol, the spreadsheets default currency format is used.
 func (s *Styler) CurrencyFormat() *sheets.NumberFormat {
+	var pattern string
+	if s.negativeStyle != "" || s.currencySymbol != "" {
+		style := s.negativeStyle
+		if style == "" {
+			style = NegativeMinus
+		}
+		pattern = negativePattern(s.currencyLiteral()+`#,##0.00_)`, style)
+	}
+
 	return &sheets.NumberFormat{
-		Type: "CURRENCY",
+		Pattern: pattern,
+		Type:    "CURRENCY",
 	}
 }
 
 // AccountingFormat: Provides the default accounting formatting using the styler.
 func (s *Styler) AccountingFormat() *sheets.NumberFormat {
+	symbol := s.currencyLiteral()
+	negative := `_(` + symbol + `* \(#,##0.00\)`
+	switch s.negativeStyle {
+	case NegativeMinus:
+		negative = `_(` + symbol + `* -#,##0.00_)`
+	case NegativeRed:
+		negative = `[Red]_(` + symbol + `* -#,##0.00_)`
+	}
+
 	return &sheets.NumberFormat{
-		Pattern: `_("$"* #,##0.00_);_("$"* \(#,##0.00\);_("$"* "-"??_);_(@_)`,
+		Pattern: `_(` + symbol + `* #,##0.00_);` + negative + `;_(` + symbol + `* "-"??_);_(@_)`,
 		Type:    "NUMBER",
 	}
 }
 
-// TextCell: Creates a new sheets text cell using the stylers settings for the formatting.
-func (s *Styler) TextCell(value string, borders *BorderConf) *sheets.CellData {
+// cellFormat: Provides a new sheets cell format using the stylers settings.
+// numberFormat and borders are optional and will be left unset if nil.
+func (s *Styler) cellFormat(numberFormat *sheets.NumberFormat, borders *BorderConf) *sheets.CellFormat {
 	format := sheets.CellFormat{
-		HorizontalAlignment: s.horizontalAlignment,
-		TextFormat:          s.TextFormat(),
-		VerticalAlignment:   s.verticalAlignment,
+		BackgroundColorStyle: s.backgroundColor,
+		HorizontalAlignment:  s.horizontalAlignment,
+		NumberFormat:         numberFormat,
+		TextDirection:        s.textDirection,
+		TextFormat:           s.TextFormat(),
+		TextRotation:         s.textRotation,
+		VerticalAlignment:    s.verticalAlignment,
+		WrapStrategy:         s.wrapStrategy,
 	}
 	if borders != nil {
 		format.Borders = CellBorders(borders)
 	}
 
+	return &format
+}
+
+// TextCell: Creates a new sheets text cell using the stylers settings for the formatting.
+func (s *Styler) TextCell(value string, borders *BorderConf) *sheets.CellData {
+	format := s.cellFormat(nil, borders)
+
 	return &sheets.CellData{
-		UserEnteredFormat: &format,
+		UserEnteredFormat: format,
 		UserEnteredValue:  TextValue(value),
 	}
 }
 
-// BoolCell: Creates a new sheets bool cell using the stylers settings for the formatting.
-func (s *Styler) BoolCell(value bool, borders *BorderConf) *sheets.CellData {
-	format := sheets.CellFormat{
-		HorizontalAlignment: s.horizontalAlignment,
-		TextFormat:          s.TextFormat(),
-		VerticalAlignment:   s.verticalAlignment,
+// indentPixels is the left padding added for each level of an indented cell.
+const indentPixels = 12
+
+// IndentCell: Creates a new sheets text cell indented by the given level using the stylers settings for the formatting.
+// Each level adds 12 pixels of left padding to the default 3, so hierarchical data can be shown in a single column.
+// Levels below 0 are treated as 0.
+func (s *Styler) IndentCell(value string, level int, borders *BorderConf) *sheets.CellData {
+	level = max(level, 0)
+
+	format := s.cellFormat(nil, borders)
+	format.Padding = &sheets.Padding{
+		Bottom: 2,
+		Left:   int64(3 + level*indentPixels),
+		Right:  3,
+		Top:    2,
 	}
-	if borders != nil {
-		format.Borders = CellBorders(borders)
+
+	return &sheets.CellData{
+		UserEnteredFormat: format,
+		UserEnteredValue:  TextValue(value),
+	}
+}
+
+// OverflowCell: Creates a new sheets text cell that overflows into the empty cells to its right,
+// using the stylers settings for the rest of the formatting.
+// Useful for long labels that shouldn't wrap or be clipped, without changing the wrap strategy of every cell.
+func (s *Styler) OverflowCell(value string, borders *BorderConf) *sheets.CellData {
+	format := s.cellFormat(nil, borders)
+	format.WrapStrategy = "OVERFLOW_CELL"
+
+	return &sheets.CellData{
+		UserEnteredFormat: format,
+		UserEnteredValue:  TextValue(value),
 	}
+}
+
+// HyperlinkCell: Creates a new sheets cell linking to the url with the given display text,
+// using the stylers settings for the formatting. Double quotes in the text and url are escaped.
+func (s *Styler) HyperlinkCell(text, url string, borders *BorderConf) *sheets.CellData {
+	format := s.cellFormat(nil, borders)
+
+	return &sheets.CellData{
+		UserEnteredFormat: format,
+		UserEnteredValue:  FormulaValue("=HYPERLINK(" + formulaString(url) + "," + formulaString(text) + ")"),
+	}
+}
+
+// BoolCell: Creates a new sheets bool cell using the stylers settings for the formatting.
+func (s *Styler) BoolCell(value bool, borders *BorderConf) *sheets.CellData {
+	format := s.cellFormat(nil, borders)
 
 	return &sheets.CellData{
-		UserEnteredFormat: &format,
+		UserEnteredFormat: format,
 		UserEnteredValue:  BoolValue(value),
 	}
 }
 
 // CheckBoxCell: Creates a new sheets checkbox cell using the stylers settings for the formatting.
 func (s *Styler) CheckBoxCell(value bool, borders *BorderConf) *sheets.CellData {
-	bc := sheets.BooleanCondition{
-		Type: "BOOLEAN",
-	}
-	dv := sheets.DataValidationRule{
-		Condition: &bc,
-		Strict:    true,
+	format := s.cellFormat(nil, borders)
+
+	return &sheets.CellData{
+		DataValidation:    CheckBoxValidation(nil),
+		UserEnteredFormat: format,
+		UserEnteredValue:  BoolValue(value),
 	}
-	format := sheets.CellFormat{
-		HorizontalAlignment: s.horizontalAlignment,
-		TextFormat:          s.TextFormat(),
-		VerticalAlignment:   s.verticalAlignment,
+}
+
+// YesNoCell: Creates a new sheets text cell for the boolean value, using the stylers settings for the formatting.
+// The value is written as the yes or no text, which default to "Yes" and "No" when empty,
+// for sheets where a checkbox or TRUE/FALSE isn't wanted.
+func (s *Styler) YesNoCell(value bool, yes, no string, borders *BorderConf) *sheets.CellData {
+	if yes == "" {
+		yes = "Yes"
 	}
-	if borders != nil {
-		format.Borders = CellBorders(borders)
+	if no == "" {
+		no = "No"
 	}
 
-	return &sheets.CellData{
-		DataValidation:    &dv,
-		UserEnteredFormat: &format,
-		UserEnteredValue:  BoolValue(value),
+	text := no
+	if value {
+		text = yes
 	}
+	return s.TextCell(text, borders)
 }
 
 // NumberCell: Creates a new sheets text cell using the stylers settings for the formatting.
 func (s *Styler) NumberCell(value float64, borders *BorderConf) *sheets.CellData {
-	format := sheets.CellFormat{
-		HorizontalAlignment: s.horizontalAlignment,
-		NumberFormat:        s.NumberFormat(),
-		TextFormat:          s.TextFormat(),
-		VerticalAlignment:   s.verticalAlignment,
+	format := s.cellFormat(s.NumberFormat(), borders)
+
+	return &sheets.CellData{
+		UserEnteredFormat: format,
+		UserEnteredValue:  NumberValue(value),
 	}
-	if borders != nil {
-		format.Borders = CellBorders(borders)
+}
+
+// PercentCell: Creates a new sheets percent cell using the stylers settings for the formatting.
+// The value is a ratio, so 0.25 is displayed as 25.00% with the default pattern.
+func (s *Styler) PercentCell(value float64, borders *BorderConf) *sheets.CellData {
+	format := s.cellFormat(s.PercentFormat(), borders)
+
+	return &sheets.CellData{
+		UserEnteredFormat: format,
+		UserEnteredValue:  NumberValue(value),
 	}
+}
+
+// NumberCellRounded: Creates a new sheets number cell with the value rounded to the given number of decimal places,
+// using the stylers settings for the formatting. Decimals below 0 are treated as 0.
+//
+// Unlike the number format, which only rounds how the value is displayed, the rounded value is what gets stored,
+// so formulas referencing the cell use it too.
+func (s *Styler) NumberCellRounded(value float64, decimals int, borders *BorderConf) *sheets.CellData {
+	decimals = max(decimals, 0)
+
+	// Shift the decimal point in the values shortest representation rather than multiplying,
+	// so values like 1.005 round the way they are written instead of by their binary approximation.
+	mantissa, exp, _ := strings.Cut(strconv.FormatFloat(value, 'e', -1, 64), "e")
+	e, _ := strconv.Atoi(exp)
+	shifted, err := strconv.ParseFloat(mantissa+"e"+strconv.Itoa(e+decimals), 64)
+	if err != nil || math.IsInf(shifted, 0) {
+		return s.NumberCell(value, borders)
+	}
+
+	// Halves round away from zero, the same as the ROUND function.
+	return s.NumberCell(math.Round(shifted)/math.Pow10(decimals), borders)
+}
+
+// FormattedNumberCell: Creates a new sheets number cell displayed with the given number format,
+// using the stylers settings for the rest of the formatting.
+// The value is stored as a real number, so formulas like SUM still work on it no matter how it is displayed,
+// which isn't the case when a pre-formatted string is written with TextCell.
+func (s *Styler) FormattedNumberCell(value float64, nf *sheets.NumberFormat, borders *BorderConf) *sheets.CellData {
+	format := s.cellFormat(nf, borders)
 
 	return &sheets.CellData{
-		UserEnteredFormat: &format,
+		UserEnteredFormat: format,
 		UserEnteredValue:  NumberValue(value),
 	}
 }
 
 // AccountingCell: Creates a new sheets accounting cell using the stylers settings for the formatting.
 func (s *Styler) AccountingCell(value float64, borders *BorderConf) *sheets.CellData {
-	format := sheets.CellFormat{
-		HorizontalAlignment: s.horizontalAlignment,
-		NumberFormat:        s.AccountingFormat(),
-		TextFormat:          s.TextFormat(),
-		VerticalAlignment:   s.verticalAlignment,
-	}
-	if borders != nil {
-		format.Borders = CellBorders(borders)
-	}
+	format := s.cellFormat(s.AccountingFormat(), borders)
 
 	return &sheets.CellData{
-		UserEnteredFormat: &format,
+		UserEnteredFormat: format,
 		UserEnteredValue:  NumberValue(value),
 	}
 }
@@ -467,23 +1280,155 @@ func (s *Styler) DateCell(date, layout string, borders *BorderConf) *sheets.Cell
 		return s.TextCell(date, borders)
 	}
 
-	format := sheets.CellFormat{
-		HorizontalAlignment: s.horizontalAlignment,
-		NumberFormat:        s.DateFormat(),
-		TextFormat:          s.TextFormat(),
-		VerticalAlignment:   s.verticalAlignment,
+	format := s.cellFormat(s.DateFormat(), borders)
+
+	return &sheets.CellData{
+		UserEnteredFormat: format,
+		UserEnteredValue:  NumberValue(serialDate),
 	}
+}
 
-	if borders != nil {
-		format.Borders = CellBorders(borders)
+// DateTimeCell: Creates a new sheets date time cell using the stylers settings for the formatting.
+// Unlike DateCell, the time of day is kept, so timestamps on the same date stay in order.
+// The value is displayed with the default date time pattern for the spreadsheets locale.
+func (s *Styler) DateTimeCell(value, layout string, borders *BorderConf) *sheets.CellData {
+	serialDate, err := SerialDate(value, layout)
+	if err != nil {
+		return s.TextCell(value, borders)
 	}
 
+	format := s.cellFormat(&sheets.NumberFormat{Type: "DATE_TIME"}, borders)
+
 	return &sheets.CellData{
-		UserEnteredFormat: &format,
+		UserEnteredFormat: format,
 		UserEnteredValue:  NumberValue(serialDate),
 	}
 }
 
+// TimeCell: Creates a new sheets time cell using the stylers settings for the formatting.
+// Only the time of day is kept, stored as the fraction of the day, so "18:00" is 0.75.
+// The value is displayed with the default time pattern for the spreadsheets locale.
+func (s *Styler) TimeCell(value, layout string, borders *BorderConf) *sheets.CellData {
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return s.TextCell(value, borders)
+	}
+
+	format := s.cellFormat(&sheets.NumberFormat{Type: "TIME"}, borders)
+
+	return &sheets.CellData{
+		UserEnteredFormat: format,
+		UserEnteredValue:  NumberValue(timeOfDaySerial(t)),
+	}
+}
+
+// timeOfDaySerial: Returns the fraction of the day for the times wall clock, the serial number of a time without a date.
+func timeOfDaySerial(t time.Time) float64 {
+	clock := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+	return clock.Hours() / 24
+}
+
+// dataSourceColumnPattern matches references to a data source sheet column, like "trips!fare" or "'Taxi Trips'!fare".
+var dataSourceColumnPattern = regexp.MustCompile(`(?:'(?:[^']|'')+'|[A-Za-z_][\w.]*)!([A-Za-z_]\w*)`)
+
+// DataSourceCell: Creates a new sheets cell with a formula referencing a connected data source,
+// like a BigQuery connected sheet, using the stylers settings for the formatting.
+//
+// The data source is resolved from the formula, so it has to reference a column of a DATA_SOURCE sheet
+// by name, like "=SUM(trips!fare)". Formulas that don't start with "=" or only reference regular A1 cells are rejected.
+func (s *Styler) DataSourceCell(formula string, borders *BorderConf) (*sheets.CellData, error) {
+	if !strings.HasPrefix(formula, "=") || !referencesDataSource(formula) {
+		return nil, ErrDataSourceCell
+	}
+
+	format := s.cellFormat(nil, borders)
+
+	return &sheets.CellData{
+		UserEnteredFormat: format,
+		UserEnteredValue:  FormulaValue(formula),
+	}, nil
+}
+
+// referencesDataSource: Reports whether the formula references a data source column instead of only A1 cells.
+func referencesDataSource(formula string) bool {
+	for _, match := range dataSourceColumnPattern.FindAllStringSubmatch(formula, -1) {
+		if !isCell(match[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// SparklineCell: Creates a new sheets cell with a SPARKLINE formula for the data range using the stylers settings for the formatting.
+// opts holds the sparkline options, like "charttype" and "color". Numbers and booleans are passed as is,
+// and everything else is passed as a quoted string.
+func (s *Styler) SparklineCell(dataRange string, opts map[string]string, borders *BorderConf) *sheets.CellData {
+	formula := "=SPARKLINE(" + dataRange
+	if len(opts) > 0 {
+		var keys []string
+		for key := range opts {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var options []string
+		for _, key := range keys {
+			options = append(options, formulaString(key)+","+sparklineOption(opts[key]))
+		}
+		formula += ",{" + strings.Join(options, ";") + "}"
+	}
+	formula += ")"
+
+	format := s.cellFormat(nil, borders)
+
+	return &sheets.CellData{
+		UserEnteredFormat: format,
+		UserEnteredValue:  FormulaValue(formula),
+	}
+}
+
+// sparklineOption: Returns the sparkline option value as it should appear in the formula.
+func sparklineOption(value string) string {
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	if strings.EqualFold(value, "TRUE") || strings.EqualFold(value, "FALSE") {
+		return strings.ToUpper(value)
+	}
+	return formulaString(value)
+}
+
+// formulaString: Returns the value as a quoted formula string, doubling any quotes in it.
+func formulaString(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+}
+
+// DateValidationCell: Creates a new sheets date cell with the given data validation rule,
+// like DateAfterTodayValidation, using the stylers settings for the formatting.
+func (s *Styler) DateValidationCell(date, layout string, rule *sheets.DataValidationRule, borders *BorderConf) *sheets.CellData {
+	cell := s.DateCell(date, layout, borders)
+	cell.DataValidation = rule
+	return cell
+}
+
+// ConditionalCheckBoxCell: Creates a new sheets boolean cell that only accepts input while the formula is true,
+// like "=$A2<>\"\"" to only allow checking it once column A of the row is set, using the stylers settings
+// for the formatting. See CustomFormulaValidation for how the formula is used.
+//
+// Sheets only draws a checkbox for cells with a BOOLEAN rule, and a cell holds a single rule, so the
+// custom formula rule replaces it and the value is shown as TRUE or FALSE.
+func (s *Styler) ConditionalCheckBoxCell(value bool, formula string, strict bool, borders *BorderConf) (*sheets.CellData, error) {
+	rule, err := CustomFormulaValidation(formula, strict)
+	if err != nil {
+		return nil, err
+	}
+
+	cell := s.BoolCell(value, borders)
+	cell.DataValidation = rule
+	return cell, nil
+}
+
 // CreateHeaderRow: Creates the header row with the given header values.
 func (s *Styler) CreateHeaderRow(headerValues []string, borders *BorderConf) []*sheets.RowData {
 	var rows []*sheets.RowData
@@ -0,0 +1,1297 @@
+package rwsheets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// negativeSection: Returns the negative section of the number pattern.
+func negativeSection(pattern string) string {
+	sections := strings.Split(pattern, ";")
+	if len(sections) < 2 {
+		return ""
+	}
+	return sections[1]
+}
+
+func TestStylerNegativeStyle(t *testing.T) {
+	tests := []struct {
+		style      string
+		number     string
+		currency   string
+		accounting string
+	}{
+		{NegativeParens, "(#,##0.00)", `("$"#,##0.00)`, `_("$"* \(#,##0.00\)`},
+		{NegativeMinus, "-#,##0.00", `-"$"#,##0.00`, `_("$"* -#,##0.00_)`},
+		{NegativeRed, "[Red]-#,##0.00", `[Red]-"$"#,##0.00`, `[Red]_("$"* -#,##0.00_)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			s := NewStyler().NegativeStyle(tt.style)
+
+			if got := negativeSection(s.NumberFormat().Pattern); got != tt.number {
+				t.Errorf("number negative section = %q, want %q", got, tt.number)
+			}
+			if got := negativeSection(s.CurrencyFormat().Pattern); got != tt.currency {
+				t.Errorf("currency negative section = %q, want %q", got, tt.currency)
+			}
+			if got := negativeSection(s.AccountingFormat().Pattern); got != tt.accounting {
+				t.Errorf("accounting negative section = %q, want %q", got, tt.accounting)
+			}
+		})
+	}
+}
+
+func TestStylerNegativeStyleOrder(t *testing.T) {
+	// The style should apply no matter when the number pattern is set.
+	before := NewStyler().NegativeStyle("minus").NumberPattern("0.00").NumberFormat().Pattern
+	after := NewStyler().NumberPattern("0.00").NegativeStyle("minus").NumberFormat().Pattern
+
+	if before != "0.00;-0.00" || after != before {
+		t.Errorf("patterns = %q and %q, want both %q", before, after, "0.00;-0.00")
+	}
+}
+
+func TestStylerNegativeStyleInvalid(t *testing.T) {
+	s := NewStyler().NegativeStyle("BLUE")
+
+	if got := s.NumberFormat().Pattern; got != "#,##0.00_);-#,##0.00" {
+		t.Errorf("pattern = %q, want the default", got)
+	}
+	if got := s.CurrencyFormat().Pattern; got != "" {
+		t.Errorf("currency pattern = %q, want the default", got)
+	}
+}
+
+func TestStylerTextDirection(t *testing.T) {
+	s := NewStyler().TextDirection("RIGHT_TO_LEFT")
+	if got := s.TextCell("שלום", nil).UserEnteredFormat.TextDirection; got != "RIGHT_TO_LEFT" {
+		t.Errorf("text direction = %q, want RIGHT_TO_LEFT", got)
+	}
+
+	// Invalid directions leave the styler unchanged.
+	s.TextDirection("UP")
+	if got := s.NumberCell(1, nil).UserEnteredFormat.TextDirection; got != "RIGHT_TO_LEFT" {
+		t.Errorf("text direction = %q, want RIGHT_TO_LEFT", got)
+	}
+
+	if got := NewStyler().TextCell("hi", nil).UserEnteredFormat.TextDirection; got != "" {
+		t.Errorf("default text direction = %q, want it unset", got)
+	}
+}
+
+func TestGetSheetDataIndexed(t *testing.T) {
+	number := &sheets.CellData{EffectiveValue: &sheets.ExtendedValue{NumberValue: new(float64)}}
+	*number.EffectiveValue.NumberValue = 42
+
+	srv, _ := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		// C5:D6, with an empty cell in the first row.
+		return http.StatusOK, gridResponse(4, 2,
+			&sheets.RowData{Values: []*sheets.CellData{{}, {EffectiveValue: TextValue("x")}}},
+			&sheets.RowData{Values: []*sheets.CellData{number}},
+		)
+	})
+
+	values, err := GetSheetDataIndexed("ssid", "Data!C5:D6", srv)
+	if err != nil {
+		t.Fatalf("GetSheetDataIndexed: %v", err)
+	}
+
+	want := map[string]interface{}{"D5": "x", "C6": 42.0}
+	if len(values) != len(want) {
+		t.Errorf("values = %v, want %v", values, want)
+	}
+	for ref, value := range want {
+		if values[ref] != value {
+			t.Errorf("values[%s] = %v, want %v", ref, values[ref], value)
+		}
+	}
+}
+
+// windowedGrid: Returns a handler that serves rows from the given range of the sheet,
+// like the API does, along with the row count for metadata requests.
+func windowedGrid(t *testing.T, sheetRows []*sheets.RowData) fakeHandler {
+	return func(call fakeCall) (int, interface{}) {
+		if call.Query.Get("fields") != "" {
+			return http.StatusOK, &sheets.Spreadsheet{
+				Sheets: []*sheets.Sheet{{
+					Properties: &sheets.SheetProperties{
+						GridProperties: &sheets.GridProperties{RowCount: int64(len(sheetRows))},
+					},
+				}},
+			}
+		}
+
+		_, gr, err := parseA1(call.Query.Get("ranges"))
+		if err != nil {
+			t.Errorf("parseA1: %v", err)
+			return http.StatusBadRequest, apiError(http.StatusBadRequest)
+		}
+
+		// Trailing empty rows are left off, like the API does.
+		end := min(gr.EndRowIndex, int64(len(sheetRows)))
+		for end > gr.StartRowIndex && len(sheetRows[end-1].Values) == 0 {
+			end--
+		}
+		return http.StatusOK, gridResponse(gr.StartRowIndex, 0, sheetRows[gr.StartRowIndex:end]...)
+	}
+}
+
+func TestGetSheetDataPaged(t *testing.T) {
+	var sheetRows []*sheets.RowData
+	for i := 0; i < 10; i++ {
+		sheetRows = append(sheetRows, textRow(CellRef(int64(i), 0)))
+	}
+	// An empty row inside a window, and two at the end of the sheet.
+	sheetRows[4] = &sheets.RowData{}
+	sheetRows = append(sheetRows, &sheets.RowData{}, &sheets.RowData{})
+
+	srv, api := newFakeService(t, windowedGrid(t, sheetRows))
+
+	rows, err := GetSheetDataPaged("ssid", "Sheet1", 3, srv)
+	if err != nil {
+		t.Fatalf("GetSheetDataPaged: %v", err)
+	}
+
+	if len(rows) != 10 {
+		t.Fatalf("got %d rows, want 10", len(rows))
+	}
+	for i, row := range rows {
+		want := CellRef(int64(i), 0)
+		if i == 4 {
+			want = ""
+		}
+		if got := cellString(cellAt(row, 0)); got != want {
+			t.Errorf("row %d = %q, want %q", i, got, want)
+		}
+	}
+
+	var requested []string
+	for _, call := range api.Calls()[1:] {
+		requested = append(requested, call.Query.Get("ranges"))
+	}
+	want := "Sheet1!1:3 Sheet1!4:6 Sheet1!7:9 Sheet1!10:12"
+	if strings.Join(requested, " ") != want {
+		t.Errorf("requested %v, want %s", requested, want)
+	}
+}
+
+func TestGetSheetDataPagedContext(t *testing.T) {
+	srv, api := newFakeService(t, windowedGrid(t, []*sheets.RowData{textRow("a"), textRow("b")}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := GetSheetDataPagedContext(ctx, "ssid", "Sheet1!A1:A2", 1, srv); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if calls := api.Calls(); len(calls) != 0 {
+		t.Errorf("got %d calls, want none", len(calls))
+	}
+}
+
+func TestGetSheetDataPagedInvalidPageSize(t *testing.T) {
+	srv, _ := newFakeService(t, nil)
+
+	if _, err := GetSheetDataPaged("ssid", "Sheet1", 0, srv); !errors.Is(err, ErrPageSize) {
+		t.Errorf("err = %v, want ErrPageSize", err)
+	}
+}
+
+func TestForEachRow(t *testing.T) {
+	var sheetRows []*sheets.RowData
+	for i := 0; i < 7; i++ {
+		sheetRows = append(sheetRows, textRow(CellRef(int64(i), 0)))
+	}
+	srv, _ := newFakeService(t, windowedGrid(t, sheetRows))
+
+	var seen []int
+	err := ForEachRow(context.Background(), "ssid", "Sheet1!A2:A7", 2, func(rowIndex int, row *sheets.RowData) error {
+		if got, want := cellString(cellAt(row, 0)), CellRef(int64(rowIndex), 0); got != want {
+			t.Errorf("row %d = %q, want %q", rowIndex, got, want)
+		}
+		seen = append(seen, rowIndex)
+		return nil
+	}, srv)
+	if err != nil {
+		t.Fatalf("ForEachRow: %v", err)
+	}
+
+	if fmt.Sprint(seen) != "[1 2 3 4 5 6]" {
+		t.Errorf("saw rows %v, want 1 through 6 in order", seen)
+	}
+}
+
+func TestForEachRowEarlyExit(t *testing.T) {
+	var sheetRows []*sheets.RowData
+	for i := 0; i < 10; i++ {
+		sheetRows = append(sheetRows, textRow("x"))
+	}
+	srv, api := newFakeService(t, windowedGrid(t, sheetRows))
+
+	errStop := errors.New("stop")
+	var seen int
+	err := ForEachRow(context.Background(), "ssid", "Sheet1!A1:A10", 3, func(rowIndex int, row *sheets.RowData) error {
+		seen++
+		if rowIndex == 4 {
+			return errStop
+		}
+		return nil
+	}, srv)
+
+	if !errors.Is(err, errStop) {
+		t.Errorf("err = %v, want the callbacks error", err)
+	}
+	if seen != 5 {
+		t.Errorf("saw %d rows, want 5", seen)
+	}
+	// Only the first two pages should have been read.
+	if calls := len(api.Calls()); calls != 2 {
+		t.Errorf("got %d calls, want 2", calls)
+	}
+}
+
+func TestForEachRowCancelled(t *testing.T) {
+	srv, _ := newFakeService(t, windowedGrid(t, []*sheets.RowData{textRow("a"), textRow("b"), textRow("c")}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var seen int
+	err := ForEachRow(ctx, "ssid", "Sheet1!A1:A3", 5, func(rowIndex int, row *sheets.RowData) error {
+		seen++
+		cancel()
+		return nil
+	}, srv)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if seen != 1 {
+		t.Errorf("saw %d rows, want 1", seen)
+	}
+}
+
+func TestStylerDataSourceCell(t *testing.T) {
+	for _, formula := range []string{"=SUM(trips!fare)", "=COUNTUNIQUE('Taxi Trips'!company)"} {
+		cell, err := NewStyler().DataSourceCell(formula, nil)
+		if err != nil {
+			t.Errorf("DataSourceCell(%q): %v", formula, err)
+			continue
+		}
+		if got := *cell.UserEnteredValue.FormulaValue; got != formula {
+			t.Errorf("formula = %q, want %q", got, formula)
+		}
+		// The data source formula is output only, so it shouldn't be written.
+		if cell.DataSourceFormula != nil {
+			t.Errorf("data source formula = %+v, want nil", cell.DataSourceFormula)
+		}
+	}
+}
+
+func TestStylerDataSourceCellInvalid(t *testing.T) {
+	for _, formula := range []string{"SUM(trips!fare)", "=SUM(Sheet1!A1:A10)", "=SUM(A:A)", "=1+1"} {
+		if _, err := NewStyler().DataSourceCell(formula, nil); !errors.Is(err, ErrDataSourceCell) {
+			t.Errorf("DataSourceCell(%q) err = %v, want ErrDataSourceCell", formula, err)
+		}
+	}
+}
+
+func TestFindRowByKey(t *testing.T) {
+	srv, _ := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		// A3:B6, with a duplicate key and a row missing the key column.
+		return http.StatusOK, gridResponse(2, 0,
+			textRow("id", "name"),
+			textRow("7", "first"),
+			&sheets.RowData{},
+			textRow("7", "second"),
+		)
+	})
+
+	index, row, err := FindRowByKey("ssid", "Sheet1!A3:B6", "7", 0, srv)
+	if err != nil {
+		t.Fatalf("FindRowByKey: %v", err)
+	}
+	if index != 3 || cellString(cellAt(row, 1)) != "first" {
+		t.Errorf("found row %d %q, want row 3 \"first\"", index, cellString(cellAt(row, 1)))
+	}
+
+	if _, _, err := FindRowByKey("ssid", "Sheet1!A3:B6", "8", 0, srv); !errors.Is(err, ErrNoData) {
+		t.Errorf("err = %v, want ErrNoData", err)
+	}
+}
+
+// upsertSheet: Returns a handler serving a sheet titled "People" with the given rows.
+func upsertSheet(rows ...*sheets.RowData) fakeHandler {
+	return func(call fakeCall) (int, interface{}) {
+		switch {
+		case strings.HasSuffix(call.Path, ":batchUpdate"):
+			return http.StatusOK, &sheets.BatchUpdateSpreadsheetResponse{}
+		case call.Query.Get("includeGridData") == "true":
+			if call.Query.Get("ranges") != "People" {
+				return http.StatusBadRequest, apiError(http.StatusBadRequest)
+			}
+			return http.StatusOK, gridResponse(0, 0, rows...)
+		}
+		return http.StatusOK, &sheets.Spreadsheet{
+			Sheets: []*sheets.Sheet{{Properties: &sheets.SheetProperties{SheetId: 4, Title: "People"}}},
+		}
+	}
+}
+
+func TestUpsertUpdate(t *testing.T) {
+	srv, api := newFakeService(t, upsertSheet(textRow("id", "name"), textRow("1", "ann"), textRow("2", "bob")))
+
+	if err := Upsert("ssid", 4, 0, "2", textRow("2", "robert"), srv); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 1 || requests[0].UpdateCells == nil {
+		t.Fatalf("requests = %+v, want a single UpdateCells", requests)
+	}
+
+	update := requests[0].UpdateCells
+	if r := update.Range; r.SheetId != 4 || r.StartRowIndex != 2 || r.EndRowIndex != 3 || r.EndColumnIndex != 2 {
+		t.Errorf("range = %+v, want row 2", r)
+	}
+	if got := cellString(cellAt(update.Rows[0], 1)); got != "robert" {
+		t.Errorf("name = %q, want robert", got)
+	}
+}
+
+func TestUpsertInsert(t *testing.T) {
+	srv, api := newFakeService(t, upsertSheet(textRow("id", "name"), textRow("1", "ann")))
+
+	if err := Upsert("ssid", 4, 0, "3", textRow("3", "cy"), srv); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 1 || requests[0].AppendCells == nil {
+		t.Fatalf("requests = %+v, want a single AppendCells", requests)
+	}
+	if req := requests[0].AppendCells; req.SheetId != 4 || cellString(cellAt(req.Rows[0], 1)) != "cy" {
+		t.Errorf("append = %+v", req)
+	}
+}
+
+func TestMapCells(t *testing.T) {
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		if strings.HasSuffix(call.Path, ":batchUpdate") {
+			return http.StatusOK, &sheets.BatchUpdateSpreadsheetResponse{}
+		}
+		// B2:C3, with a number and an already uppercase cell that shouldn't be rewritten.
+		return http.StatusOK, gridResponse(1, 1,
+			textRow("abc", "DONE"),
+			&sheets.RowData{Values: []*sheets.CellData{{UserEnteredValue: NumberValue(5)}, {UserEnteredValue: TextValue("x")}}},
+		)
+	})
+
+	upper := func(c *sheets.CellData) *sheets.CellData {
+		if c.UserEnteredValue == nil || c.UserEnteredValue.StringValue == nil {
+			return nil
+		}
+		c.UserEnteredValue = TextValue(strings.ToUpper(*c.UserEnteredValue.StringValue))
+		return c
+	}
+	if err := MapCells("ssid", "Sheet1!B2:C3", 0, upper, srv); err != nil {
+		t.Fatalf("MapCells: %v", err)
+	}
+
+	requests := api.Requests()
+	var written []string
+	for _, r := range requests {
+		u := r.UpdateCells
+		if u.Fields != cellFields {
+			t.Errorf("fields = %q, want %q", u.Fields, cellFields)
+		}
+		written = append(written, CellRef(u.Range.StartRowIndex, u.Range.StartColumnIndex)+"="+cellString(cellAt(u.Rows[0], 0)))
+	}
+	if got := strings.Join(written, " "); got != "B2=ABC C3=X" {
+		t.Errorf("wrote %s, want B2=ABC C3=X", got)
+	}
+}
+
+func TestMapCellsUnchanged(t *testing.T) {
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusOK, gridResponse(0, 0, textRow("a"))
+	})
+
+	if err := MapCells("ssid", "A1", 0, func(c *sheets.CellData) *sheets.CellData { return nil }, srv); err != nil {
+		t.Fatalf("MapCells: %v", err)
+	}
+	if updates := api.BatchUpdates(); len(updates) != 0 {
+		t.Errorf("got %d batch updates, want none", len(updates))
+	}
+}
+
+func TestStylerFormattedNumberCell(t *testing.T) {
+	nf := NumberFormat(`0.0 "kg"`)
+	cell := NewStyler().FormattedNumberCell(12.5, nf, RIGHT_BORDER)
+
+	if v := cell.UserEnteredValue; v.NumberValue == nil || *v.NumberValue != 12.5 || v.StringValue != nil {
+		t.Errorf("value = %+v, want the number 12.5", v)
+	}
+	if got := cell.UserEnteredFormat.NumberFormat; got != nf {
+		t.Errorf("number format = %+v, want %+v", got, nf)
+	}
+	if cell.UserEnteredFormat.Borders.Right == nil {
+		t.Error("missing the right border")
+	}
+}
+
+func TestGetSheetDataWithOptionsPadToWidth(t *testing.T) {
+	rows := []*sheets.RowData{
+		textRow("a", "b", "c"),
+		textRow("d"),
+		{},
+		textRow("e", "f"),
+	}
+	srv, _ := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusOK, gridResponse(0, 0, rows...)
+	})
+
+	tests := []struct {
+		readRange string
+		width     int
+	}{
+		{"Sheet1!A1:D4", 4},
+		// Without an end column the widest row is used.
+		{"Sheet1", 3},
+	}
+
+	for _, tt := range tests {
+		got, err := GetSheetDataWithOptions("ssid", tt.readRange, ReadOptions{PadToWidth: true}, srv)
+		if err != nil {
+			t.Fatalf("GetSheetDataWithOptions: %v", err)
+		}
+		for i, row := range got {
+			if len(row.Values) != max(tt.width, len(rows[i].Values)) {
+				t.Errorf("%s: row %d has %d cells, want %d", tt.readRange, i, len(row.Values), tt.width)
+			}
+		}
+		if cellString(cellAt(got[1], 0)) != "d" {
+			t.Errorf("%s: padding moved the row's values", tt.readRange)
+		}
+	}
+}
+
+func TestStylerIndentCell(t *testing.T) {
+	tests := []struct {
+		level int
+		left  int64
+	}{
+		{-1, 3},
+		{0, 3},
+		{1, 15},
+		{3, 39},
+	}
+
+	for _, tt := range tests {
+		cell := NewStyler().IndentCell("item", tt.level, nil)
+		if got := cell.UserEnteredFormat.Padding.Left; got != tt.left {
+			t.Errorf("level %d left padding = %d, want %d", tt.level, got, tt.left)
+		}
+		if got := *cell.UserEnteredValue.StringValue; got != "item" {
+			t.Errorf("value = %q, want item", got)
+		}
+	}
+}
+
+func TestStylerSparklineCell(t *testing.T) {
+	tests := []struct {
+		opts map[string]string
+		want string
+	}{
+		{
+			map[string]string{"charttype": "line", "color": "red", "linewidth": "2"},
+			`=SPARKLINE(A2:F2,{"charttype","line";"color","red";"linewidth",2})`,
+		},
+		{
+			map[string]string{"rtl": "true", "color": `say "hi"`},
+			`=SPARKLINE(A2:F2,{"color","say ""hi""";"rtl",TRUE})`,
+		},
+		{nil, `=SPARKLINE(A2:F2)`},
+	}
+
+	for _, tt := range tests {
+		cell := NewStyler().SparklineCell("A2:F2", tt.opts, nil)
+		if got := *cell.UserEnteredValue.FormulaValue; got != tt.want {
+			t.Errorf("formula = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestStylerYesNoCell(t *testing.T) {
+	s := NewStyler()
+
+	tests := []struct {
+		value   bool
+		yes, no string
+		want    string
+	}{
+		{true, "", "", "Yes"},
+		{false, "", "", "No"},
+		{true, "Paid", "Unpaid", "Paid"},
+		{false, "Paid", "Unpaid", "Unpaid"},
+		{false, "Paid", "", "No"},
+	}
+
+	for _, tt := range tests {
+		cell := s.YesNoCell(tt.value, tt.yes, tt.no, nil)
+		if v := cell.UserEnteredValue; v == nil || v.StringValue == nil || *v.StringValue != tt.want {
+			t.Errorf("YesNoCell(%v, %q, %q) = %+v, want %q", tt.value, tt.yes, tt.no, v, tt.want)
+		}
+		if cell.UserEnteredValue.BoolValue != nil || cell.DataValidation != nil {
+			t.Errorf("YesNoCell(%v) wrote a boolean or checkbox", tt.value)
+		}
+	}
+}
+
+func TestAppendRows(t *testing.T) {
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusOK, sheetList("Summary", "My Data")
+	})
+
+	bold := &sheets.CellFormat{TextFormat: &sheets.TextFormat{Bold: true}}
+	rows := []*sheets.RowData{
+		{Values: []*sheets.CellData{{UserEnteredValue: TextValue("a"), UserEnteredFormat: bold}}},
+		textRow("b", "c"),
+	}
+
+	if err := AppendRows("ssid", "'My Data'!A:D", rows, srv); err != nil {
+		t.Fatalf("AppendRows: %v", err)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 1 || requests[0].AppendCells == nil {
+		t.Fatalf("requests = %+v, want a single AppendCells", requests)
+	}
+
+	req := requests[0].AppendCells
+	if req.SheetId != 1 || req.Fields != "*" {
+		t.Errorf("request = %+v, want sheet 1 with every field", req)
+	}
+	if got := gridValues(req.Rows); got != "a|b,c" {
+		t.Errorf("rows = %s, want a|b,c", got)
+	}
+	if f := req.Rows[0].Values[0].UserEnteredFormat; f == nil || !f.TextFormat.Bold {
+		t.Errorf("format = %+v, want the cells formatting kept", f)
+	}
+}
+
+func TestAppendRowsStartColumn(t *testing.T) {
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusOK, sheetList("Summary", "Log")
+	})
+
+	rows := []*sheets.RowData{textRow("a", "b")}
+	if err := AppendRows("ssid", "Log!C:D", rows, srv); err != nil {
+		t.Fatalf("AppendRows: %v", err)
+	}
+
+	req := api.Requests()[0].AppendCells
+	if req.SheetId != 1 || gridValues(req.Rows) != ",,a,b" {
+		t.Errorf("sheet = %d, rows = %s, want sheet 1 starting in column C", req.SheetId, gridValues(req.Rows))
+	}
+	if len(rows[0].Values) != 2 {
+		t.Error("the given rows were modified")
+	}
+}
+
+func TestAppendRowsErrors(t *testing.T) {
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusOK, sheetList("Summary")
+	})
+
+	if err := AppendRows("ssid", "Log", nil, srv); err != nil || len(api.Calls()) != 0 {
+		t.Errorf("no rows = %v with %d calls, want nothing done", err, len(api.Calls()))
+	}
+	if err := AppendRows("ssid", "Log", []*sheets.RowData{textRow("a")}, srv); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("err = %v, want ErrSheetNotFound", err)
+	}
+}
+
+func TestStylerOverflowCell(t *testing.T) {
+	s := NewStyler().FontBold(true)
+
+	cell := s.OverflowCell("A very long section label", nil)
+	if got := cell.UserEnteredFormat.WrapStrategy; got != "OVERFLOW_CELL" {
+		t.Errorf("wrap strategy = %q, want OVERFLOW_CELL", got)
+	}
+	if v := cell.UserEnteredValue; v.StringValue == nil || *v.StringValue != "A very long section label" {
+		t.Errorf("value = %+v", v)
+	}
+	if !cell.UserEnteredFormat.TextFormat.Bold {
+		t.Error("the stylers formatting wasn't used")
+	}
+
+	// Other cells keep the default wrap strategy.
+	if got := s.TextCell("label", nil).UserEnteredFormat.WrapStrategy; got != "" {
+		t.Errorf("text cell wrap strategy = %q, want it unset", got)
+	}
+}
+
+func TestStylerNumberCellRounded(t *testing.T) {
+	s := NewStyler()
+
+	tests := []struct {
+		value    float64
+		decimals int
+		want     float64
+	}{
+		{3.14159, 2, 3.14},
+		{2.5, 0, 3},
+		{-1.2345, 3, -1.235},
+		{7.777, -1, 8},
+		{10, 2, 10},
+		{1.005, 2, 1.01},
+	}
+
+	for _, tt := range tests {
+		cell := s.NumberCellRounded(tt.value, tt.decimals, nil)
+		if v := cell.UserEnteredValue; v.NumberValue == nil || *v.NumberValue != tt.want {
+			t.Errorf("NumberCellRounded(%v, %d) stored %+v, want %v", tt.value, tt.decimals, v, tt.want)
+		}
+	}
+
+	// The number format is the same as a regular number cell.
+	cell := s.NumberCellRounded(3.14159, 2, nil)
+	if got := cell.UserEnteredFormat.NumberFormat.Pattern; got != s.NumberFormat().Pattern {
+		t.Errorf("pattern = %q, want the stylers number pattern", got)
+	}
+}
+
+func TestStylerHyperlinkCell(t *testing.T) {
+	s := NewStyler().HorizontalAlignment("CENTER")
+
+	tests := []struct {
+		text, url, want string
+	}{
+		{"Order 42", "https://example.com/orders/42", `=HYPERLINK("https://example.com/orders/42","Order 42")`},
+		{`The "best" order`, `https://example.com/?q="a"`, `=HYPERLINK("https://example.com/?q=""a""","The ""best"" order")`},
+		{"", "https://example.com", `=HYPERLINK("https://example.com","")`},
+	}
+
+	for _, tt := range tests {
+		cell := s.HyperlinkCell(tt.text, tt.url, nil)
+		if v := cell.UserEnteredValue; v.FormulaValue == nil || *v.FormulaValue != tt.want {
+			t.Errorf("HyperlinkCell(%q, %q) = %+v, want %s", tt.text, tt.url, v, tt.want)
+		}
+		if f := cell.UserEnteredFormat; f.HorizontalAlignment != "CENTER" || f.TextFormat == nil {
+			t.Errorf("format = %+v, want the stylers alignment and text format", f)
+		}
+	}
+}
+
+func TestGetRowsSince(t *testing.T) {
+	// The sheet has 20 rows in its grid, but only the first few have data.
+	var sheetRows []*sheets.RowData
+	grow := func(values ...string) {
+		for _, value := range values {
+			sheetRows = append(sheetRows, textRow(value))
+		}
+	}
+	grid := func() []*sheets.RowData {
+		padded := append([]*sheets.RowData(nil), sheetRows...)
+		for len(padded) < 20 {
+			padded = append(padded, &sheets.RowData{})
+		}
+		return padded
+	}
+
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return windowedGrid(t, grid())(call)
+	})
+
+	grow("a", "b", "c")
+	rows, next, err := GetRowsSince("ssid", "Log", 0, srv)
+	if err != nil {
+		t.Fatalf("GetRowsSince: %v", err)
+	}
+	if gridValues(rows) != "a|b|c" || next != 3 {
+		t.Errorf("first poll = %s, %d, want a|b|c, 3", gridValues(rows), next)
+	}
+
+	// Nothing has been added since.
+	rows, next, err = GetRowsSince("ssid", "Log", next, srv)
+	if err != nil {
+		t.Fatalf("GetRowsSince: %v", err)
+	}
+	if len(rows) != 0 || next != 3 {
+		t.Errorf("second poll = %s, %d, want no rows and 3", gridValues(rows), next)
+	}
+
+	grow("d", "e")
+	rows, next, err = GetRowsSince("ssid", "Log", next, srv)
+	if err != nil {
+		t.Fatalf("GetRowsSince: %v", err)
+	}
+	if gridValues(rows) != "d|e" || next != 5 {
+		t.Errorf("third poll = %s, %d, want d|e, 5", gridValues(rows), next)
+	}
+
+	calls := api.Calls()
+	if got := calls[len(calls)-1].Query.Get("ranges"); got != "'Log'!4:20" {
+		t.Errorf("ranges = %q, want 'Log'!4:20", got)
+	}
+}
+
+func TestGetRowsSinceEndOfGrid(t *testing.T) {
+	srv, api := newFakeService(t, windowedGrid(t, []*sheets.RowData{textRow("a")}))
+
+	rows, next, err := GetRowsSince("ssid", "Log", 1, srv)
+	if err != nil || len(rows) != 0 || next != 1 {
+		t.Errorf("got %d rows, %d, %v, want no rows and 1", len(rows), next, err)
+	}
+	if len(api.Calls()) != 1 {
+		t.Errorf("got %d calls, want only the row count", len(api.Calls()))
+	}
+
+	if _, _, err := GetRowsSince("ssid", "Log", -1, srv); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("err = %v, want ErrInvalidRange", err)
+	}
+}
+
+func TestStylerPercentCell(t *testing.T) {
+	s := NewStyler().FontSize(12).HorizontalAlignment("RIGHT")
+
+	cell := s.PercentCell(0.25, nil)
+	if v := cell.UserEnteredValue; v.NumberValue == nil || *v.NumberValue != 0.25 {
+		t.Errorf("value = %+v, want the ratio 0.25", v)
+	}
+	f := cell.UserEnteredFormat
+	if nf := f.NumberFormat; nf.Type != "PERCENT" || nf.Pattern != "0.00%" {
+		t.Errorf("number format = %+v, want the default percent format", nf)
+	}
+	if f.HorizontalAlignment != "RIGHT" || f.TextFormat.FontSize != 12 {
+		t.Errorf("format = %+v, want the stylers alignment and font", f)
+	}
+
+	cell = s.PercentPattern("0.0%").PercentCell(0.125, nil)
+	if got := cell.UserEnteredFormat.NumberFormat.Pattern; got != "0.0%" {
+		t.Errorf("pattern = %q, want 0.0%%", got)
+	}
+}
+
+func TestStylerDateTimeCell(t *testing.T) {
+	s := NewStyler()
+
+	// Two events on the same date keep their order.
+	morning := s.DateTimeCell("2024-01-01 06:00", "2006-01-02 15:04", nil)
+	evening := s.DateTimeCell("2024-01-01 18:00", "2006-01-02 15:04", nil)
+	if v := morning.UserEnteredValue; v.NumberValue == nil || *v.NumberValue != 45292.25 {
+		t.Errorf("morning = %+v, want 45292.25", v)
+	}
+	if v := evening.UserEnteredValue; v.NumberValue == nil || *v.NumberValue != 45292.75 {
+		t.Errorf("evening = %+v, want 45292.75", v)
+	}
+	if nf := morning.UserEnteredFormat.NumberFormat; nf.Type != "DATE_TIME" {
+		t.Errorf("number format = %+v, want DATE_TIME", nf)
+	}
+
+	// Values that don't match the layout are written as text.
+	cell := s.DateTimeCell("soon", "2006-01-02 15:04", nil)
+	if v := cell.UserEnteredValue; v.StringValue == nil || *v.StringValue != "soon" {
+		t.Errorf("invalid value = %+v, want the text", v)
+	}
+}
+
+func TestStylerTimeCell(t *testing.T) {
+	s := NewStyler()
+
+	tests := []struct {
+		value, layout string
+		want          float64
+	}{
+		{"18:00", "15:04", 0.75},
+		{"6:00 AM", "3:04 PM", 0.25},
+		{"00:00:00", "15:04:05", 0},
+		{"2024-01-01 12:00", "2006-01-02 15:04", 0.5},
+	}
+
+	for _, tt := range tests {
+		cell := s.TimeCell(tt.value, tt.layout, nil)
+		if v := cell.UserEnteredValue; v.NumberValue == nil || *v.NumberValue != tt.want {
+			t.Errorf("TimeCell(%q) = %+v, want %v", tt.value, v, tt.want)
+		}
+		if nf := cell.UserEnteredFormat.NumberFormat; nf.Type != "TIME" {
+			t.Errorf("number format = %+v, want TIME", nf)
+		}
+	}
+}
+
+func TestStylerWithAlignment(t *testing.T) {
+	s := NewStyler().HorizontalAlignment("CENTER")
+	before := *s
+
+	right := s.WithAlignment("RIGHT")
+	if right == s {
+		t.Fatal("WithAlignment returned the receiver")
+	}
+	if got := right.TextCell("x", nil).UserEnteredFormat.HorizontalAlignment; got != "RIGHT" {
+		t.Errorf("copy alignment = %q, want RIGHT", got)
+	}
+	if !reflect.DeepEqual(*s, before) {
+		t.Errorf("receiver = %+v, want it unchanged from %+v", *s, before)
+	}
+	if got := s.TextCell("x", nil).UserEnteredFormat.HorizontalAlignment; got != "CENTER" {
+		t.Errorf("receiver alignment = %q, want CENTER", got)
+	}
+
+	// Changing the copy in place doesn't leak back either.
+	right.FontBold(true).NumberPattern("0")
+	if !reflect.DeepEqual(*s, before) {
+		t.Errorf("receiver = %+v after changing the copy", *s)
+	}
+
+	bold := s.WithFontBold(true).WithFontSize(14).WithVerticalAlignment("TOP").WithDatePattern("yyyy").WithNumberPattern("0.0")
+	want := NewStyler().HorizontalAlignment("CENTER").FontBold(true).FontSize(14).VerticalAlignment("TOP").DatePattern("yyyy").NumberPattern("0.0")
+	if !reflect.DeepEqual(bold, want) {
+		t.Errorf("chained copy = %+v, want %+v", bold, want)
+	}
+	if !reflect.DeepEqual(*s, before) {
+		t.Errorf("receiver = %+v after chaining", *s)
+	}
+}
+
+func TestSerialDate(t *testing.T) {
+	tests := []struct {
+		value, layout string
+		want          float64
+	}{
+		{"1899-12-30", "2006-01-02", 0},
+		{"1900-01-01", "2006-01-02", 2},
+		{"2024-01-01", "2006-01-02", 45292},
+		{"2024-03-10 06:00", "2006-01-02 15:04", 45361.25},
+		{"2024-03-10 12:00", "2006-01-02 15:04", 45361.5},
+		{"2024-03-10 18:00:00.5", "2006-01-02 15:04:05", 45361.75 + 0.5/secondsPerDay},
+		// Offsets and zones don't move the wall clock time, including across a daylight saving change.
+		{"2024-03-10 06:00 -0500", "2006-01-02 15:04 -0700", 45361.25},
+		{"2024-03-10 06:00 +1400", "2006-01-02 15:04 -0700", 45361.25},
+		{"2024-03-10 06:00 EDT", "2006-01-02 15:04 MST", 45361.25},
+		// Dates far from the epoch don't overflow.
+		{"1500-01-01", "2006-01-02", -146095},
+		{"2500-01-01", "2006-01-02", 219148},
+	}
+
+	for _, tt := range tests {
+		got, err := SerialDate(tt.value, tt.layout)
+		if err != nil {
+			t.Fatalf("SerialDate(%q): %v", tt.value, err)
+		}
+		if got != tt.want {
+			t.Errorf("SerialDate(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+
+	if _, err := SerialDate("not a date", "2006-01-02"); err == nil {
+		t.Error("expected an error for an invalid date")
+	}
+}
+
+func TestSerialDateLocalTime(t *testing.T) {
+	// A time in a zone with daylight saving uses its wall clock on either side of the change.
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	before := time.Date(2024, 3, 10, 1, 0, 0, 0, loc)
+	after := time.Date(2024, 3, 10, 6, 0, 0, 0, loc)
+	if got := timeToSerial(before); got != 45361+1.0/24 {
+		t.Errorf("before the change = %v, want %v", got, 45361+1.0/24)
+	}
+	if got := timeToSerial(after); got != 45361.25 {
+		t.Errorf("after the change = %v, want 45361.25", got)
+	}
+}
+
+func TestSerialToTime(t *testing.T) {
+	tests := []struct {
+		serial float64
+		want   time.Time
+	}{
+		{0, time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)},
+		{45000.5, time.Date(2023, 3, 15, 12, 0, 0, 0, time.UTC)},
+		{45361.25, time.Date(2024, 3, 10, 6, 0, 0, 0, time.UTC)},
+		{-1.25, time.Date(1899, 12, 28, 18, 0, 0, 0, time.UTC)},
+		{-146095, time.Date(1500, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// Floating point noise is rounded away.
+		{45000.5 + 1e-9, time.Date(2023, 3, 15, 12, 0, 0, 0, time.UTC)},
+		{45000 + 0.9999999999, time.Date(2023, 3, 16, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		if got := SerialToTime(tt.serial); !got.Equal(tt.want) {
+			t.Errorf("SerialToTime(%v) = %v, want %v", tt.serial, got, tt.want)
+		}
+	}
+}
+
+func TestSerialToTimeRoundTrip(t *testing.T) {
+	const layout = "2006-01-02 15:04:05.000"
+	values := []string{
+		"1899-12-30 00:00:00.000",
+		"1850-06-15 08:30:00.000",
+		"2024-01-01 00:00:00.000",
+		"2024-03-10 06:00:00.000",
+		"2024-12-31 23:59:59.999",
+	}
+
+	for _, value := range values {
+		serial, err := SerialDate(value, layout)
+		if err != nil {
+			t.Fatalf("SerialDate(%q): %v", value, err)
+		}
+		if got := SerialToTime(serial).Format(layout); got != value {
+			t.Errorf("round trip of %q = %q (serial %v)", value, got, serial)
+		}
+	}
+}
+
+func TestGetSheetDataRich(t *testing.T) {
+	value := 1234.5
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusOK, gridResponse(1, 0, &sheets.RowData{Values: []*sheets.CellData{{
+			EffectiveValue:    &sheets.ExtendedValue{NumberValue: &value},
+			UserEnteredFormat: &sheets.CellFormat{NumberFormat: &sheets.NumberFormat{Type: "CURRENCY"}},
+		}}})
+	})
+
+	const mask = "userEnteredValue,effectiveValue,userEnteredFormat.numberFormat"
+	rows, err := GetSheetDataRich("ssid", "Totals!A2:A2", mask, srv)
+	if err != nil {
+		t.Fatalf("GetSheetDataRich: %v", err)
+	}
+	if len(rows) != 1 || len(rows[0].Values) != 1 {
+		t.Fatalf("rows = %+v, want a single cell", rows)
+	}
+	if cell := rows[0].Values[0]; *cell.EffectiveValue.NumberValue != 1234.5 || cell.UserEnteredFormat.NumberFormat.Type != "CURRENCY" {
+		t.Errorf("cell = %+v", cell)
+	}
+
+	call := api.Calls()[0]
+	if got, want := call.Query.Get("fields"), "sheets(data(startRow,startColumn,rowData(values("+mask+"))))"; got != want {
+		t.Errorf("fields = %q, want %q", got, want)
+	}
+	if got := call.Query.Get("ranges"); got != "Totals!A2:A2" {
+		t.Errorf("ranges = %q", got)
+	}
+}
+
+func TestGetSheetDataRichDefaults(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	if _, err := GetSheetDataRich("ssid", "Totals", "", srv); !errors.Is(err, ErrNoData) {
+		t.Errorf("err = %v, want ErrNoData", err)
+	}
+	if got := api.Calls()[0].Query.Get("fields"); got != "sheets(data(startRow,startColumn,rowData(values(*))))" {
+		t.Errorf("fields = %q, want every cell field", got)
+	}
+}
+
+func TestSerialDateInLocation(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	tests := []struct {
+		value, layout string
+		loc           *time.Location
+		want          float64
+	}{
+		// Zoneless values are already on the locations wall clock.
+		{"2024-03-10 23:30", "2006-01-02 15:04", la, 45361 + 23.5/24},
+		// A UTC timestamp early on the 10th happened on the 9th in Los Angeles.
+		{"2024-03-10T07:30:00Z", time.RFC3339, la, 45360 + 23.5/24},
+		{"2024-03-10T07:30:00Z", time.RFC3339, time.UTC, 45361 + 7.5/24},
+		{"2024-03-10T07:30:00Z", time.RFC3339, nil, 45361 + 7.5/24},
+		// Both sides of the daylight saving change use the local wall clock.
+		{"2024-03-10T09:00:00Z", time.RFC3339, la, 45361 + 1.0/24},
+		{"2024-03-10T11:00:00Z", time.RFC3339, la, 45361 + 4.0/24},
+		{"2024-03-10 12:00 -0500", "2006-01-02 15:04 -0700", la, 45361 + 10.0/24},
+	}
+
+	for _, tt := range tests {
+		got, err := SerialDateInLocation(tt.value, tt.layout, tt.loc)
+		if err != nil {
+			t.Fatalf("SerialDateInLocation(%q): %v", tt.value, err)
+		}
+		if got != tt.want {
+			t.Errorf("SerialDateInLocation(%q, %v) = %v, want %v", tt.value, tt.loc, got, tt.want)
+		}
+	}
+
+	if _, err := SerialDateInLocation("not a date", time.RFC3339, la); err == nil {
+		t.Error("expected an error for an invalid date")
+	}
+}
+
+func TestStylerBackgroundColor(t *testing.T) {
+	s := NewStyler()
+
+	// Without a background the field is left unset.
+	if bg := s.TextCell("x", nil).UserEnteredFormat.BackgroundColorStyle; bg != nil {
+		t.Errorf("background = %+v, want it unset", bg)
+	}
+
+	shade := ColorRGB(0.95, 0.95, 0.95)
+	s.BackgroundColor(shade)
+	cells := []*sheets.CellData{
+		s.TextCell("x", nil),
+		s.NumberCell(1, nil),
+		s.DateCell("2024-01-01", "2006-01-02", nil),
+		s.CheckBoxCell(true, nil),
+		s.RichTextCell([]TextRun{{Text: "x"}}, nil),
+	}
+	for i, cell := range cells {
+		if bg := cell.UserEnteredFormat.BackgroundColorStyle; bg != shade {
+			t.Errorf("cell %d background = %+v, want the stylers", i, bg)
+		}
+	}
+
+	if bg := s.BackgroundColor(nil).TextCell("x", nil).UserEnteredFormat.BackgroundColorStyle; bg != nil {
+		t.Errorf("background = %+v after clearing it", bg)
+	}
+}
+
+func TestStylerTextColor(t *testing.T) {
+	s := NewStyler().FontBold(true)
+
+	// Without a color the text format is the same as before.
+	want := &sheets.TextFormat{Bold: true, FontFamily: "Verdana", FontSize: 10}
+	if got := s.TextFormat(); !reflect.DeepEqual(got, want) {
+		t.Errorf("text format = %+v, want %+v", got, want)
+	}
+
+	red := ColorRGB(0.8, 0, 0)
+	cell := s.TextColor(red).NumberCell(-12.5, nil)
+	tf := cell.UserEnteredFormat.TextFormat
+	if tf.ForegroundColorStyle != red || !tf.Bold || tf.FontFamily != "Verdana" {
+		t.Errorf("text format = %+v, want the red color with the stylers font", tf)
+	}
+
+	// Text runs can still override the color.
+	blue := ColorRGB(0, 0, 1)
+	rich := s.RichTextCell([]TextRun{{Text: "a"}, {Text: "b", Color: blue}}, nil)
+	if rich.UserEnteredFormat.TextFormat.ForegroundColorStyle != red || rich.TextFormatRuns[1].Format.ForegroundColorStyle != blue {
+		t.Errorf("rich text = %+v", rich)
+	}
+}
+
+func TestStylerFontStyles(t *testing.T) {
+	s := NewStyler()
+
+	tf := s.TextFormat()
+	if tf.Italic || tf.Underline || tf.Strikethrough {
+		t.Errorf("default text format = %+v, want no font styles", tf)
+	}
+
+	red := ColorRGB(0.8, 0, 0)
+	cell := s.Italic(true).Underline(true).Strikethrough(true).TextColor(red).TextCell("Cancelled", nil)
+	tf = cell.UserEnteredFormat.TextFormat
+	if !tf.Italic || !tf.Underline || !tf.Strikethrough || tf.ForegroundColorStyle != red {
+		t.Errorf("text format = %+v, want italic, underlined and struck through red text", tf)
+	}
+
+	tf = s.Underline(false).TextFormat()
+	if !tf.Italic || tf.Underline || !tf.Strikethrough {
+		t.Errorf("text format = %+v, want only the underline removed", tf)
+	}
+}
+
+func TestStylerWrapStrategy(t *testing.T) {
+	s := NewStyler()
+
+	if got := s.WrapStrategy("WRAP").TextCell("line one\nline two", nil).UserEnteredFormat.WrapStrategy; got != "WRAP" {
+		t.Errorf("wrap strategy = %q, want WRAP", got)
+	}
+	if got := s.NumberCell(1, nil).UserEnteredFormat.WrapStrategy; got != "WRAP" {
+		t.Errorf("number cell wrap strategy = %q, want WRAP", got)
+	}
+
+	// Invalid strategies leave the styler unchanged.
+	for _, strategy := range []string{"", "wrap", "SHRINK"} {
+		if got := s.WrapStrategy(strategy).TextCell("x", nil).UserEnteredFormat.WrapStrategy; got != "WRAP" {
+			t.Errorf("WrapStrategy(%q) changed the strategy to %q", strategy, got)
+		}
+	}
+
+	// Overflow cells always overflow.
+	if got := s.OverflowCell("x", nil).UserEnteredFormat.WrapStrategy; got != "OVERFLOW_CELL" {
+		t.Errorf("overflow cell wrap strategy = %q", got)
+	}
+	if got := s.WrapStrategy("CLIP").TextCell("x", nil).UserEnteredFormat.WrapStrategy; got != "CLIP" {
+		t.Errorf("wrap strategy = %q, want CLIP", got)
+	}
+}
+
+func TestStylerTextRotation(t *testing.T) {
+	s := NewStyler()
+
+	if r := s.TextCell("x", nil).UserEnteredFormat.TextRotation; r != nil {
+		t.Errorf("rotation = %+v, want it unset", r)
+	}
+
+	r := s.TextRotationAngle(45).TextCell("Revenue", nil).UserEnteredFormat.TextRotation
+	if r == nil || r.Angle != 45 || r.Vertical {
+		t.Errorf("rotation = %+v, want 45 degrees", r)
+	}
+
+	// Angles the API rejects leave the rotation unchanged.
+	for _, angle := range []int64{-91, 91, 180} {
+		if r := s.TextRotationAngle(angle).textRotation; r.Angle != 45 {
+			t.Errorf("TextRotationAngle(%d) changed the rotation to %+v", angle, r)
+		}
+	}
+
+	r = s.TextRotationVertical(true).TextCell("x", nil).UserEnteredFormat.TextRotation
+	if r == nil || !r.Vertical || r.Angle != 0 {
+		t.Errorf("rotation = %+v, want vertical text", r)
+	}
+	if s.TextRotationVertical(false).textRotation != nil {
+		t.Error("vertical false didn't remove the rotation")
+	}
+
+	// An angle of 0 is still sent, resetting any rotation.
+	cell := s.TextRotationAngle(0).TextCell("x", nil)
+	data, err := json.Marshal(cell.UserEnteredFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"textRotation":{"angle":0}`) {
+		t.Errorf("format = %s, want an angle of 0", data)
+	}
+}
+
+func TestCellBorders(t *testing.T) {
+	// Only the shared settings, the same as before per side settings were added.
+	borders := CellBorders(&BorderConf{Bottom: true, Left: true})
+	if b := borders.Bottom; b == nil || b.Style != "SOLID" || b.ColorStyle != BLACK_COLOR {
+		t.Errorf("bottom = %+v, want a solid black border", b)
+	}
+	if borders.Left == nil || borders.Right != nil || borders.Top != nil {
+		t.Errorf("borders = %+v, want only the bottom and left", borders)
+	}
+
+	dark := ColorRGB(0.2, 0.2, 0.2)
+	gray := ColorRGB(0.8, 0.8, 0.8)
+	borders = CellBorders(&BorderConf{
+		Bottom:      true,
+		Left:        true,
+		Right:       true,
+		Top:         true,
+		Color:       gray,
+		BottomColor: dark,
+		BottomStyle: "SOLID_THICK",
+		TopStyle:    "DOTTED",
+	})
+
+	tests := []struct {
+		name   string
+		border *sheets.Border
+		color  *sheets.ColorStyle
+		style  string
+	}{
+		{"bottom", borders.Bottom, dark, "SOLID_THICK"},
+		{"left", borders.Left, gray, "SOLID"},
+		{"right", borders.Right, gray, "SOLID"},
+		{"top", borders.Top, gray, "DOTTED"},
+	}
+	for _, tt := range tests {
+		if tt.border == nil || tt.border.ColorStyle != tt.color || tt.border.Style != tt.style {
+			t.Errorf("%s = %+v, want %s in %+v", tt.name, tt.border, tt.style, tt.color)
+		}
+	}
+
+	// Per side settings are ignored for sides that aren't set.
+	borders = CellBorders(&BorderConf{Top: true, BottomColor: dark})
+	if borders.Bottom != nil || borders.Top.ColorStyle != BLACK_COLOR {
+		t.Errorf("borders = %+v, want only a black top border", borders)
+	}
+}
+
+func TestStylerDecimals(t *testing.T) {
+	// The default pattern is the same as before decimals were added.
+	if got := NewStyler().NumberFormat().Pattern; got != "#,##0.00_);-#,##0.00" {
+		t.Errorf("default pattern = %q", got)
+	}
+
+	tests := []struct {
+		decimals int
+		want     string
+	}{
+		{0, "#,##0_);-#,##0"},
+		{-2, "#,##0_);-#,##0"},
+		{1, "#,##0.0_);-#,##0.0"},
+		{4, "#,##0.0000_);-#,##0.0000"},
+	}
+	for _, tt := range tests {
+		pattern := NewStyler().Decimals(tt.decimals).NumberCell(1, nil).UserEnteredFormat.NumberFormat.Pattern
+		if pattern != tt.want {
+			t.Errorf("Decimals(%d) pattern = %q, want %q", tt.decimals, pattern, tt.want)
+		}
+		if err := ValidateNumberPattern(pattern); err != nil {
+			t.Errorf("Decimals(%d) pattern: %v", tt.decimals, err)
+		}
+	}
+
+	// An explicit pattern wins no matter the order, and the negative style still applies.
+	s := NewStyler().NumberPattern("0.0").Decimals(3)
+	if got := s.NumberFormat().Pattern; got != "0.0" {
+		t.Errorf("pattern = %q, want the explicit pattern", got)
+	}
+	if got := s.NumberPattern("").NegativeStyle(NegativeRed).NumberFormat().Pattern; got != "#,##0.000_);[Red]-#,##0.000" {
+		t.Errorf("pattern = %q, want 3 decimals in red", got)
+	}
+}
+
+func TestStylerCurrencySymbol(t *testing.T) {
+	// Without a symbol or negative style the default currency format is unchanged.
+	if got := NewStyler().CurrencyFormat(); got.Type != "CURRENCY" || got.Pattern != "" {
+		t.Errorf("default currency format = %+v", got)
+	}
+
+	tests := []struct {
+		symbol     string
+		style      string
+		currency   string
+		accounting string
+	}{
+		{"€", "", `"€"#,##0.00_);-"€"#,##0.00`, `_("€"* #,##0.00_);_("€"* \(#,##0.00\);_("€"* "-"??_);_(@_)`},
+		{"£", NegativeParens, `"£"#,##0.00_);("£"#,##0.00)`, `_("£"* #,##0.00_);_("£"* \(#,##0.00\);_("£"* "-"??_);_(@_)`},
+		{"¥", NegativeRed, `"¥"#,##0.00_);[Red]-"¥"#,##0.00`, `_("¥"* #,##0.00_);[Red]_("¥"* -#,##0.00_);_("¥"* "-"??_);_(@_)`},
+		{`C"HF`, NegativeMinus, `"CHF"#,##0.00_);-"CHF"#,##0.00`, `_("CHF"* #,##0.00_);_("CHF"* -#,##0.00_);_("CHF"* "-"??_);_(@_)`},
+		{"", NegativeMinus, `"$"#,##0.00_);-"$"#,##0.00`, `_("$"* #,##0.00_);_("$"* -#,##0.00_);_("$"* "-"??_);_(@_)`},
+	}
+
+	for _, tt := range tests {
+		s := NewStyler().CurrencySymbol(tt.symbol).NegativeStyle(tt.style)
+
+		currency := s.CurrencyFormat()
+		if currency.Type != "CURRENCY" || currency.Pattern != tt.currency {
+			t.Errorf("%q %q currency = %+v, want %s", tt.symbol, tt.style, currency, tt.currency)
+		}
+		accounting := s.AccountingCell(-5, nil).UserEnteredFormat.NumberFormat
+		if accounting.Pattern != tt.accounting {
+			t.Errorf("%q %q accounting = %s, want %s", tt.symbol, tt.style, accounting.Pattern, tt.accounting)
+		}
+
+		for _, pattern := range []string{currency.Pattern, accounting.Pattern} {
+			if err := ValidateNumberPattern(pattern); err != nil {
+				t.Errorf("%q %q pattern %s: %v", tt.symbol, tt.style, pattern, err)
+			}
+		}
+	}
+}
@@ -1,11 +1,16 @@
 package rwsheets
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"sync"
 
 	"golang.org/x/net/context"
 	"golang.org/x/oauth2"
@@ -18,6 +23,9 @@ var (
 	ErrReadCredentials = errors.New("unable to read contents of credential file")
 	ErrReadToken       = errors.New("unable to read contents of token file")
 	ErrConfig          = errors.New("failed to create oauth2.Config")
+	ErrServiceAccount  = errors.New("key file is not a valid service account key")
+	ErrNoRefreshToken  = errors.New("saved token has expired and has no refresh token, delete the token file and authorize again")
+	ErrStateMismatch   = errors.New("authorization state doesn't match the state that was sent")
 )
 
 // NewSheetsService: Creates a new Google Sheets Service.
@@ -32,18 +40,100 @@ var (
 // https://www.googleapis.com/auth/drive.readonly - See and download all your Google Drive files
 // https://www.googleapis.com/auth/spreadsheets - See, edit, create, and delete all your Google Sheets spreadsheets
 // https://www.googleapis.com/auth/spreadsheets.readonly - See all your Google Sheets spreadsheets
+//
+// If there isn't a saved token yet, the authorization URL is printed and the code is read from the terminal.
+// Use NewSheetsServiceWithAuthCode to provide the code some other way.
 func NewSheetsService(ctx context.Context, credentialFile, tokenFile string, scope ...string) (*sheets.Service, error) {
+	return NewSheetsServiceWithAuthCode(ctx, credentialFile, tokenFile, nil, scope...)
+}
+
+// AuthCodeProvider is used to get the authorization code for a new token.
+// It is given the URL the user needs to visit to authorize the app, and should return the resulting code.
+type AuthCodeProvider func(authURL string) (string, error)
+
+// NewSheetsServiceWithAuthCode: Creates a new Google Sheets Service, using provider to get the
+// authorization code if there isn't a saved token yet. See NewSheetsService for the other parameters.
+//
+// This lets the package be used in web servers or GUIs by running your own authorization flow,
+// like opening a browser and listening for the redirect. If provider is nil the code is read from the terminal.
+// Use NewSheetsServiceWithAuthConfig to also verify the state returned with the code.
+func NewSheetsServiceWithAuthCode(ctx context.Context, credentialFile, tokenFile string, provider AuthCodeProvider, scope ...string) (*sheets.Service, error) {
+	return NewSheetsServiceWithAuthConfig(ctx, credentialFile, tokenFile, AuthConfig{Provider: provider}, scope...)
+}
+
+// AuthCallback is used to get the authorization code for a new token from the redirect back to the app.
+// It is given the URL the user needs to visit to authorize the app, and should return the code and
+// state query parameters the redirect was made with.
+type AuthCallback func(authURL string) (code, state string, err error)
+
+// AuthConfig: struct to be used to configure how a new token is authorized.
+type AuthConfig struct {
+	// RedirectURL overrides the redirect URI from the credential file, like "http://localhost:8080/callback".
+	// It has to be one of the redirect URIs registered for the OAuth client.
+	RedirectURL string
+
+	// Callback gets the code and state from the redirect. A random state is sent with each authorization
+	// URL, and the code is only exchanged for a token if the returned state matches it, protecting the
+	// flow against cross-site request forgery. Takes precedence over Provider.
+	Callback AuthCallback
+
+	// Provider gets the code without a state to verify, like when the user pastes it into the terminal.
+	// If both Callback and Provider are nil the code is read from the terminal.
+	Provider AuthCodeProvider
+}
+
+// NewSheetsServiceWithAuthConfig: Creates a new Google Sheets Service, authorizing a new token using the
+// configuration if there isn't a saved token yet. See NewSheetsService for the other parameters.
+func NewSheetsServiceWithAuthConfig(ctx context.Context, credentialFile, tokenFile string, auth AuthConfig, scope ...string) (*sheets.Service, error) {
 	config, err := getConfig(credentialFile, scope...)
 	if err != nil {
 		return nil, err
 	}
+	if auth.RedirectURL != "" {
+		config.RedirectURL = auth.RedirectURL
+	}
 
-	token, err := getToken(config, tokenFile)
+	token, err := getToken(config, tokenFile, auth)
 	if err != nil {
 		return nil, err
 	}
 
-	return sheets.NewService(ctx, option.WithTokenSource(config.TokenSource(ctx, token)))
+	return NewSheetsServiceWithTokenSource(ctx, persistentTokenSource(ctx, config, token, tokenFile))
+}
+
+// NewSheetsServiceWithTokenSource: Creates a new Google Sheets Service using the given token source.
+// This can be used to reuse credentials your app already manages, like an in-memory token.
+func NewSheetsServiceWithTokenSource(ctx context.Context, ts oauth2.TokenSource) (*sheets.Service, error) {
+	return sheets.NewService(ctx, option.WithTokenSource(ts))
+}
+
+// NewSheetsServiceWithClient: Creates a new Google Sheets Service that sends its requests with the given client.
+// The client is expected to handle authentication, like one created from Application Default Credentials,
+// and can also be used to inject a custom transport in tests.
+func NewSheetsServiceWithClient(ctx context.Context, client *http.Client) (*sheets.Service, error) {
+	return sheets.NewService(ctx, option.WithHTTPClient(client))
+}
+
+// NewServiceAccountService: Creates a new Google Sheets Service authenticated as a service account.
+//
+// keyFile should be the file path to the service account's JSON key.
+// scope should be the oAuth scope needed for the Sheets service, see NewSheetsService for the options.
+//
+// No user interaction is needed, so this can be used by servers and CI jobs. The spreadsheets
+// must be shared with the service account's email address for it to be able to access them.
+func NewServiceAccountService(ctx context.Context, keyFile string, scope ...string) (*sheets.Service, error) {
+	key, err := os.ReadFile(keyFile)
+	if err != nil {
+		log.Printf("NewServiceAccountService: unable to read service account key file: %v", err)
+		return nil, ErrReadCredentials
+	}
+
+	config, err := google.JWTConfigFromJSON(key, scope...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrServiceAccount, err)
+	}
+
+	return sheets.NewService(ctx, option.WithTokenSource(config.TokenSource(ctx)))
 }
 
 // getConfig: Retrieves the oauth2.Config using the given client credientals and scope.
@@ -66,13 +156,64 @@ func getConfig(credentialFile string, scope ...string) (*oauth2.Config, error) {
 }
 
 // getToken: Either retrieves or creates the oauth2.Token.
-func getToken(config *oauth2.Config, token string) (*oauth2.Token, error) {
+func getToken(config *oauth2.Config, token string, auth AuthConfig) (*oauth2.Token, error) {
 	// The given token file stores the user's access and refresh tokens, and is
 	// created automatically when the authorization flow completes for the first time.
 	tok, err := tokenFromFile(token)
 	if err != nil {
-		tok = getTokenFromWeb(config)
-		saveToken(token, tok)
+		tok, err = getTokenFromWeb(config, auth)
+		if err != nil {
+			return nil, err
+		}
+		if err := saveToken(token, tok); err != nil {
+			return nil, err
+		}
+		return tok, nil
+	}
+
+	// Without a refresh token the saved token can't be renewed, but it can still be used until it expires.
+	if tok.RefreshToken == "" && !tok.Valid() {
+		return nil, fmt.Errorf("%w: %s", ErrNoRefreshToken, token)
+	}
+	return tok, nil
+}
+
+// persistentTokenSource: Returns a token source that refreshes the token when it expires,
+// saving each refreshed token to the token file so the next run can reuse it.
+func persistentTokenSource(ctx context.Context, config *oauth2.Config, token *oauth2.Token, tokenFile string) oauth2.TokenSource {
+	src := &savingTokenSource{
+		src:  config.TokenSource(ctx, token),
+		path: tokenFile,
+		last: token.AccessToken,
+	}
+	return oauth2.ReuseTokenSource(token, src)
+}
+
+// savingTokenSource saves the token to a file whenever its access token changes.
+type savingTokenSource struct {
+	src  oauth2.TokenSource
+	path string
+
+	mu   sync.Mutex
+	last string
+}
+
+// Token: Returns the token from the underlying source, saving it if it was refreshed.
+// If the refreshed token can't be saved the failure is logged and the token is still returned,
+// so the API calls keep working and the token is only refreshed again once it expires.
+func (s *savingTokenSource) Token() (*oauth2.Token, error) {
+	tok, err := s.src.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if tok.AccessToken != s.last {
+		if err := saveToken(s.path, tok); err != nil {
+			log.Printf("savingTokenSource: unable to save the refreshed token: %v", err)
+		}
+		s.last = tok.AccessToken
 	}
 	return tok, nil
 }
@@ -96,37 +237,81 @@ func tokenFromFile(file string) (*oauth2.Token, error) {
 }
 
 // getTokenFromWeb: Request a token from the web, then returns the retrieved token.
-func getTokenFromWeb(config *oauth2.Config) *oauth2.Token {
-	// Create a new authorization URL.
-	authURL := config.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
-	fmt.Printf("Go to the following link in your browser then type the "+
-		"authorization code: \n%v\n", authURL)
+func getTokenFromWeb(config *oauth2.Config, auth AuthConfig) (*oauth2.Token, error) {
+	state, err := randomState()
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new authorization URL. Google only returns a refresh token the first time the user consents,
+	// so force the consent screen to get one even if the app was authorized before.
+	authURL := config.AuthCodeURL(state, oauth2.AccessTypeOffline, oauth2.ApprovalForce)
 
 	var authCode string
-	// Read the auth code from the terminal
-	if _, err := fmt.Scan(&authCode); err != nil {
-		log.Fatalf("getTokenFromWeb: unable to read authorization code: %v", err)
+	switch {
+	case auth.Callback != nil:
+		var returnedState string
+		if authCode, returnedState, err = auth.Callback(authURL); err != nil {
+			return nil, fmt.Errorf("unable to get authorization code: %w", err)
+		}
+		// Compare in constant time so the state can't be guessed from how long the check takes.
+		if subtle.ConstantTimeCompare([]byte(returnedState), []byte(state)) != 1 {
+			return nil, ErrStateMismatch
+		}
+	default:
+		provider := auth.Provider
+		if provider == nil {
+			provider = terminalAuthCode
+		}
+		if authCode, err = provider(authURL); err != nil {
+			return nil, fmt.Errorf("unable to get authorization code: %w", err)
+		}
 	}
 
 	// Create the oauth2.Token.
 	tok, err := config.Exchange(context.TODO(), authCode)
 	if err != nil {
-		log.Fatalf("getTokenFromWeb: unable to retrieve token from web: %v", err)
+		return nil, fmt.Errorf("unable to retrieve token from web: %w", err)
+	}
+	return tok, nil
+}
+
+// randomState: Returns a random state for an authorization URL.
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("unable to generate authorization state: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// terminalAuthCode: Prints the authorization URL, then reads the authorization code from the terminal.
+func terminalAuthCode(authURL string) (string, error) {
+	fmt.Printf("Go to the following link in your browser then type the "+
+		"authorization code: \n%v\n", authURL)
+
+	var authCode string
+	// Read the auth code from the terminal
+	if _, err := fmt.Scan(&authCode); err != nil {
+		return "", err
 	}
-	return tok
+	return authCode, nil
 }
 
 // saveToken: Saves a token to a file path.
-func saveToken(path string, token *oauth2.Token) {
+func saveToken(path string, token *oauth2.Token) error {
 	log.Printf("saveToken: saving file to: %s\n", path)
 
 	// Open or create the token file at the given path.
 	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
-		log.Printf("saveToken: unable to cache oauth token at path %s: %v", path, err)
+		return fmt.Errorf("unable to cache oauth token at path %s: %w", path, err)
 	}
-	defer f.Close()
 
 	// Add the contents of the token to the file.
-	json.NewEncoder(f).Encode(token)
+	if err := json.NewEncoder(f).Encode(token); err != nil {
+		f.Close()
+		return fmt.Errorf("unable to cache oauth token at path %s: %w", path, err)
+	}
+	return f.Close()
 }
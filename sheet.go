@@ -0,0 +1,178 @@
+package rwsheets
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+var (
+	ErrSheetNotFound = errors.New("sheet not found")
+	ErrSheetExists   = errors.New("a sheet with that title already exists")
+)
+
+// AddSheet: Adds a new sheet with the given title to the end of the spreadsheet, returning the new sheets ID.
+// Returns ErrSheetExists if the spreadsheet already has a sheet with the title.
+func AddSheet(ssid, title string, srv *sheets.Service) (int64, error) {
+	request := sheets.Request{
+		AddSheet: &sheets.AddSheetRequest{
+			Properties: &sheets.SheetProperties{Title: title},
+		},
+	}
+
+	resp, err := batchUpdate(ssid, []*sheets.Request{&request}, srv)
+	if err != nil {
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusBadRequest && strings.Contains(apiErr.Message, "already exists") {
+			return 0, fmt.Errorf("%w: %s: %v", ErrSheetExists, title, err)
+		}
+		return 0, err
+	}
+
+	if len(resp.Replies) == 0 || resp.Replies[0].AddSheet == nil || resp.Replies[0].AddSheet.Properties == nil {
+		return 0, ErrNoData
+	}
+	return resp.Replies[0].AddSheet.Properties.SheetId, nil
+}
+
+// DeleteSheet: Deletes the sheet with the given ID, along with all of its data.
+func DeleteSheet(ssid string, gid int64, srv *sheets.Service) error {
+	request := sheets.Request{
+		DeleteSheet: &sheets.DeleteSheetRequest{
+			SheetId: gid,
+		},
+	}
+
+	_, err := batchUpdate(ssid, []*sheets.Request{&request}, srv)
+	return err
+}
+
+// RenameSheet: Changes the title of the sheet with the given ID.
+func RenameSheet(ssid string, gid int64, newTitle string, srv *sheets.Service) error {
+	request := sheets.Request{
+		UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
+			Fields: "title",
+			Properties: &sheets.SheetProperties{
+				SheetId: gid,
+				Title:   newTitle,
+			},
+		},
+	}
+
+	_, err := batchUpdate(ssid, []*sheets.Request{&request}, srv)
+	return err
+}
+
+// SetSheetRTL: Sets whether the sheet is displayed right-to-left instead of left-to-right.
+func SetSheetRTL(ssid string, gid int64, rtl bool, srv *sheets.Service) error {
+	properties := sheets.SheetProperties{
+		SheetId:     gid,
+		RightToLeft: rtl,
+		// RightToLeft would otherwise be dropped from the request when false.
+		ForceSendFields: []string{"RightToLeft"},
+	}
+
+	request := sheets.Request{
+		UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
+			Fields:     "rightToLeft",
+			Properties: &properties,
+		},
+	}
+
+	_, err := batchUpdate(ssid, []*sheets.Request{&request}, srv)
+	return err
+}
+
+// sheetTitle: Retrieve the title of the sheet with the given ID.
+func sheetTitle(ssid string, gid int64, srv *sheets.Service) (string, error) {
+	ss, err := srv.Spreadsheets.Get(ssid).Fields("sheets.properties(sheetId,title)").Do()
+	if err != nil {
+		return "", err
+	}
+
+	for _, sheet := range ss.Sheets {
+		if sheet.Properties != nil && sheet.Properties.SheetId == gid {
+			return sheet.Properties.Title, nil
+		}
+	}
+	return "", ErrSheetNotFound
+}
+
+// SheetIDByTitle: Retrieve the ID of the sheet with the given title, matched case-sensitively.
+// Returns ErrSheetNotFound if no sheet has the title.
+func SheetIDByTitle(ssid, title string, srv *sheets.Service) (int64, error) {
+	if title == "" {
+		return 0, ErrSheetNotFound
+	}
+	return sheetID(ssid, title, srv)
+}
+
+// sheetID: Retrieve the ID of the sheet with the given title, or of the first sheet if the title is empty.
+func sheetID(ssid, title string, srv *sheets.Service) (int64, error) {
+	ss, err := srv.Spreadsheets.Get(ssid).Fields("sheets.properties(sheetId,title)").Do()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, sheet := range ss.Sheets {
+		if sheet.Properties != nil && (title == "" || sheet.Properties.Title == title) {
+			return sheet.Properties.SheetId, nil
+		}
+	}
+	return 0, ErrSheetNotFound
+}
+
+// RowMeta: struct holding how a single row of a sheet is displayed.
+type RowMeta struct {
+	Row            int64 // Zero-based index of the row in the sheet.
+	PixelSize      int64 // Height of the row in pixels.
+	HiddenByUser   bool  // True if the row was hidden by a user.
+	HiddenByFilter bool  // True if the row is hidden by a filter.
+}
+
+// GetRowMetadata: Retrieve the height and hidden state of the rows from startRow up to, but not including, endRow.
+// The rows are zero-based indices into the sheet with the given ID.
+//
+// Only the row metadata is requested, using the "sheets(data(startRow,rowMetadata(pixelSize,hiddenByUser,hiddenByFilter)))"
+// fields mask, so no cell data is read.
+func GetRowMetadata(ssid string, gid int64, startRow, endRow int64, srv *sheets.Service) ([]RowMeta, error) {
+	if startRow < 0 || endRow <= startRow {
+		return nil, fmt.Errorf("%w: rows %d to %d", ErrInvalidRange, startRow, endRow)
+	}
+
+	title, err := sheetTitle(ssid, gid, srv)
+	if err != nil {
+		return nil, err
+	}
+
+	// A1 rows are one-based and inclusive.
+	readRange := BuildRange(title, fmt.Sprintf("%d:%d", startRow+1, endRow))
+	ss, err := srv.Spreadsheets.Get(ssid).Ranges(readRange).
+		Fields("sheets(data(startRow,rowMetadata(pixelSize,hiddenByUser,hiddenByFilter)))").Do()
+	if err != nil {
+		return nil, err
+	}
+
+	grid, err := firstGrid(ss)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta []RowMeta
+	for i, rm := range grid.RowMetadata {
+		if rm == nil {
+			rm = &sheets.DimensionProperties{}
+		}
+		meta = append(meta, RowMeta{
+			Row:            grid.StartRow + int64(i),
+			PixelSize:      rm.PixelSize,
+			HiddenByUser:   rm.HiddenByUser,
+			HiddenByFilter: rm.HiddenByFilter,
+		})
+	}
+	return meta, nil
+}
@@ -0,0 +1,227 @@
+package rwsheets
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestSetSheetRTL(t *testing.T) {
+	for _, rtl := range []bool{true, false} {
+		srv, api := newFakeService(t, nil)
+
+		if err := SetSheetRTL("ssid", 5, rtl, srv); err != nil {
+			t.Fatalf("SetSheetRTL: %v", err)
+		}
+
+		requests := api.Requests()
+		if len(requests) != 1 || requests[0].UpdateSheetProperties == nil {
+			t.Fatalf("requests = %+v, want a single UpdateSheetProperties", requests)
+		}
+
+		req := requests[0].UpdateSheetProperties
+		if req.Fields != "rightToLeft" || req.Properties.SheetId != 5 || req.Properties.RightToLeft != rtl {
+			t.Errorf("request = %+v, properties = %+v", req, req.Properties)
+		}
+
+		// false has to be sent explicitly, or the sheet would keep its current direction.
+		if body := string(api.Calls()[0].Body); !strings.Contains(body, `"rightToLeft":`) {
+			t.Errorf("body %s is missing rightToLeft", body)
+		}
+	}
+}
+
+// sheetList: Returns a spreadsheet listing the sheets with the given titles, using their index as the ID.
+func sheetList(titles ...string) *sheets.Spreadsheet {
+	ss := &sheets.Spreadsheet{}
+	for i, title := range titles {
+		ss.Sheets = append(ss.Sheets, &sheets.Sheet{
+			Properties: &sheets.SheetProperties{SheetId: int64(i), Title: title},
+		})
+	}
+	return ss
+}
+
+func TestGetRowMetadata(t *testing.T) {
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		if call.Query.Get("fields") == "sheets.properties(sheetId,title)" {
+			return http.StatusOK, sheetList("Summary", "My Data")
+		}
+		return http.StatusOK, &sheets.Spreadsheet{
+			Sheets: []*sheets.Sheet{{
+				Data: []*sheets.GridData{{
+					StartRow: 2,
+					RowMetadata: []*sheets.DimensionProperties{
+						{PixelSize: 21},
+						{PixelSize: 40, HiddenByUser: true},
+						{PixelSize: 21, HiddenByFilter: true},
+					},
+				}},
+			}},
+		}
+	})
+
+	meta, err := GetRowMetadata("ssid", 1, 2, 5, srv)
+	if err != nil {
+		t.Fatalf("GetRowMetadata: %v", err)
+	}
+
+	want := []RowMeta{
+		{Row: 2, PixelSize: 21},
+		{Row: 3, PixelSize: 40, HiddenByUser: true},
+		{Row: 4, PixelSize: 21, HiddenByFilter: true},
+	}
+	if len(meta) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(meta), len(want))
+	}
+	for i := range want {
+		if meta[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, meta[i], want[i])
+		}
+	}
+
+	call := api.Calls()[1]
+	if got := call.Query.Get("ranges"); got != "'My Data'!3:5" {
+		t.Errorf("ranges = %q, want 'My Data'!3:5", got)
+	}
+	if got := call.Query.Get("fields"); got != "sheets(data(startRow,rowMetadata(pixelSize,hiddenByUser,hiddenByFilter)))" {
+		t.Errorf("fields = %q", got)
+	}
+}
+
+func TestGetRowMetadataErrors(t *testing.T) {
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusOK, sheetList("Summary")
+	})
+
+	if _, err := GetRowMetadata("ssid", 0, 5, 5, srv); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("empty range err = %v, want ErrInvalidRange", err)
+	}
+	if _, err := GetRowMetadata("ssid", 0, -1, 5, srv); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("negative start err = %v, want ErrInvalidRange", err)
+	}
+	if len(api.Calls()) != 0 {
+		t.Error("invalid ranges called the API")
+	}
+
+	if _, err := GetRowMetadata("ssid", 9, 0, 5, srv); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("missing sheet err = %v, want ErrSheetNotFound", err)
+	}
+}
+
+func TestAddSheet(t *testing.T) {
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusOK, &sheets.BatchUpdateSpreadsheetResponse{
+			Replies: []*sheets.Response{{
+				AddSheet: &sheets.AddSheetResponse{
+					Properties: &sheets.SheetProperties{SheetId: 1234, Title: "March"},
+				},
+			}},
+		}
+	})
+
+	gid, err := AddSheet("ssid", "March", srv)
+	if err != nil {
+		t.Fatalf("AddSheet: %v", err)
+	}
+	if gid != 1234 {
+		t.Errorf("gid = %d, want 1234", gid)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 1 || requests[0].AddSheet == nil || requests[0].AddSheet.Properties.Title != "March" {
+		t.Errorf("requests = %+v, want a single AddSheet for March", requests)
+	}
+}
+
+func TestAddSheetExists(t *testing.T) {
+	srv, _ := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]interface{}{
+				"code":    http.StatusBadRequest,
+				"message": `Invalid requests[0].addSheet: A sheet with the name "March" already exists. Please enter another name.`,
+			},
+		}
+	})
+
+	if _, err := AddSheet("ssid", "March", srv); !errors.Is(err, ErrSheetExists) {
+		t.Errorf("err = %v, want ErrSheetExists", err)
+	}
+
+	// Other bad requests aren't mistaken for an existing sheet.
+	srv, _ = newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusBadRequest, apiError(http.StatusBadRequest)
+	})
+	if _, err := AddSheet("ssid", "March", srv); err == nil || errors.Is(err, ErrSheetExists) {
+		t.Errorf("err = %v, want the API error", err)
+	}
+}
+
+func TestDeleteSheet(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	if err := DeleteSheet("ssid", 7, srv); err != nil {
+		t.Fatalf("DeleteSheet: %v", err)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 1 || requests[0].DeleteSheet == nil || requests[0].DeleteSheet.SheetId != 7 {
+		t.Errorf("requests = %+v, want a single DeleteSheet for sheet 7", requests)
+	}
+}
+
+func TestRenameSheet(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	if err := RenameSheet("ssid", 7, "April", srv); err != nil {
+		t.Fatalf("RenameSheet: %v", err)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 1 || requests[0].UpdateSheetProperties == nil {
+		t.Fatalf("requests = %+v, want a single UpdateSheetProperties", requests)
+	}
+
+	req := requests[0].UpdateSheetProperties
+	if req.Fields != "title" || req.Properties.SheetId != 7 || req.Properties.Title != "April" {
+		t.Errorf("request = %+v, properties = %+v", req, req.Properties)
+	}
+}
+
+func TestSheetIDByTitle(t *testing.T) {
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusOK, sheetList("Summary", "Invoices", "invoices")
+	})
+
+	gid, err := SheetIDByTitle("ssid", "Invoices", srv)
+	if err != nil {
+		t.Fatalf("SheetIDByTitle: %v", err)
+	}
+	if gid != 1 {
+		t.Errorf("gid = %d, want 1", gid)
+	}
+
+	// Titles are matched case-sensitively.
+	if gid, err := SheetIDByTitle("ssid", "invoices", srv); err != nil || gid != 2 {
+		t.Errorf("invoices = %d, %v, want 2", gid, err)
+	}
+
+	if got := api.Calls()[0].Query.Get("fields"); got != "sheets.properties(sheetId,title)" {
+		t.Errorf("fields = %q", got)
+	}
+}
+
+func TestSheetIDByTitleNotFound(t *testing.T) {
+	srv, _ := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusOK, sheetList("Summary")
+	})
+
+	for _, title := range []string{"INVOICES", "SUMMARY", ""} {
+		if _, err := SheetIDByTitle("ssid", title, srv); !errors.Is(err, ErrSheetNotFound) {
+			t.Errorf("SheetIDByTitle(%q) err = %v, want ErrSheetNotFound", title, err)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package rwsheets
+
+import (
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// SheetConfig: struct holding a sheets decorations, used to copy them from one sheet to another.
+type SheetConfig struct {
+	BandedRanges       []*sheets.BandedRange
+	BasicFilter        *sheets.BasicFilter
+	ConditionalFormats []*sheets.ConditionalFormatRule
+	FrozenColumnCount  int64
+	FrozenRowCount     int64
+	ProtectedRanges    []*sheets.ProtectedRange
+	TabColor           *sheets.ColorStyle
+}
+
+// GetSheetConfig: Retrieve the banding, filter, protected ranges, conditional formats,
+// frozen rows and columns, and tab color of the sheet.
+func GetSheetConfig(ssid string, gid int64, srv *sheets.Service) (*SheetConfig, error) {
+	ss, err := srv.Spreadsheets.Get(ssid).
+		Fields("sheets(properties(sheetId,gridProperties,tabColorStyle),bandedRanges,basicFilter,conditionalFormats,protectedRanges)").Do()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sheet := range ss.Sheets {
+		if sheet.Properties == nil || sheet.Properties.SheetId != gid {
+			continue
+		}
+
+		cfg := SheetConfig{
+			BandedRanges:       sheet.BandedRanges,
+			BasicFilter:        sheet.BasicFilter,
+			ConditionalFormats: sheet.ConditionalFormats,
+			ProtectedRanges:    sheet.ProtectedRanges,
+			TabColor:           sheet.Properties.TabColorStyle,
+		}
+		if sheet.Properties.GridProperties != nil {
+			cfg.FrozenColumnCount = sheet.Properties.GridProperties.FrozenColumnCount
+			cfg.FrozenRowCount = sheet.Properties.GridProperties.FrozenRowCount
+		}
+		return &cfg, nil
+	}
+
+	return nil, ErrSheetNotFound
+}
+
+// ApplySheetConfig: Applies the configuration to the sheet in a single batch update.
+// All the ranges in the configuration are moved onto the target sheet, and the IDs of the
+// banded and protected ranges are cleared so the API assigns new ones.
+func ApplySheetConfig(ssid string, gid int64, cfg *SheetConfig, srv *sheets.Service) error {
+	_, err := batchUpdate(ssid, sheetConfigRequests(gid, cfg), srv)
+	return err
+}
+
+// sheetConfigRequests: Creates the requests needed to apply the configuration to the sheet.
+func sheetConfigRequests(gid int64, cfg *SheetConfig) []*sheets.Request {
+	properties := sheets.SheetProperties{
+		SheetId: gid,
+		GridProperties: &sheets.GridProperties{
+			FrozenColumnCount: cfg.FrozenColumnCount,
+			FrozenRowCount:    cfg.FrozenRowCount,
+			ForceSendFields:   []string{"FrozenColumnCount", "FrozenRowCount"},
+		},
+		TabColorStyle: cfg.TabColor,
+	}
+	requests := []*sheets.Request{
+		{
+			UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
+				Fields:     "gridProperties.frozenRowCount,gridProperties.frozenColumnCount,tabColorStyle",
+				Properties: &properties,
+			},
+		},
+	}
+
+	for _, br := range cfg.BandedRanges {
+		banded := *br
+		banded.BandedRangeId = 0
+		banded.Range = rebaseRange(br.Range, gid)
+		requests = append(requests, &sheets.Request{
+			AddBanding: &sheets.AddBandingRequest{
+				BandedRange: &banded,
+			},
+		})
+	}
+
+	if cfg.BasicFilter != nil {
+		filter := *cfg.BasicFilter
+		filter.Range = rebaseRange(cfg.BasicFilter.Range, gid)
+		requests = append(requests, &sheets.Request{
+			SetBasicFilter: &sheets.SetBasicFilterRequest{
+				Filter: &filter,
+			},
+		})
+	}
+
+	for i, rule := range cfg.ConditionalFormats {
+		format := *rule
+		format.Ranges = nil
+		for _, gr := range rule.Ranges {
+			format.Ranges = append(format.Ranges, rebaseRange(gr, gid))
+		}
+		requests = append(requests, &sheets.Request{
+			AddConditionalFormatRule: &sheets.AddConditionalFormatRuleRequest{
+				Index: int64(i),
+				Rule:  &format,
+			},
+		})
+	}
+
+	for _, pr := range cfg.ProtectedRanges {
+		protected := *pr
+		protected.ProtectedRangeId = 0
+		protected.NamedRangeId = ""
+		protected.RequestingUserCanEdit = false
+		protected.Range = rebaseRange(pr.Range, gid)
+		protected.UnprotectedRanges = nil
+		for _, gr := range pr.UnprotectedRanges {
+			protected.UnprotectedRanges = append(protected.UnprotectedRanges, rebaseRange(gr, gid))
+		}
+		requests = append(requests, &sheets.Request{
+			AddProtectedRange: &sheets.AddProtectedRangeRequest{
+				ProtectedRange: &protected,
+			},
+		})
+	}
+
+	return requests
+}
+
+// rebaseRange: Returns a copy of the range moved onto the sheet with the given ID.
+func rebaseRange(gr *sheets.GridRange, gid int64) *sheets.GridRange {
+	if gr == nil {
+		return &sheets.GridRange{SheetId: gid}
+	}
+
+	rebased := *gr
+	rebased.SheetId = gid
+	return &rebased
+}
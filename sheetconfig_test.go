@@ -0,0 +1,122 @@
+package rwsheets
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// templateSheet: Returns a sheet with one of each decoration GetSheetConfig reads.
+func templateSheet(gid int64) *sheets.Sheet {
+	return &sheets.Sheet{
+		Properties: &sheets.SheetProperties{
+			SheetId: gid,
+			GridProperties: &sheets.GridProperties{
+				FrozenColumnCount: 1,
+				FrozenRowCount:    2,
+			},
+			TabColorStyle: Color(1, 0, 0, 1),
+		},
+		BandedRanges: []*sheets.BandedRange{
+			{BandedRangeId: 11, Range: &sheets.GridRange{SheetId: gid, EndRowIndex: 10}},
+		},
+		BasicFilter: &sheets.BasicFilter{
+			Range: &sheets.GridRange{SheetId: gid, EndColumnIndex: 5},
+		},
+		ConditionalFormats: []*sheets.ConditionalFormatRule{
+			ColorScaleRule(&sheets.GridRange{SheetId: gid, StartColumnIndex: 2, EndColumnIndex: 3}, Color(1, 1, 1, 1), nil, Color(1, 0, 1, 0)),
+		},
+		ProtectedRanges: []*sheets.ProtectedRange{
+			{
+				ProtectedRangeId:      22,
+				Range:                 &sheets.GridRange{SheetId: gid},
+				RequestingUserCanEdit: true,
+				UnprotectedRanges:     []*sheets.GridRange{{SheetId: gid, StartRowIndex: 2}},
+			},
+		},
+	}
+}
+
+func TestGetSheetConfig(t *testing.T) {
+	srv, _ := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusOK, &sheets.Spreadsheet{
+			Sheets: []*sheets.Sheet{
+				{Properties: &sheets.SheetProperties{SheetId: 1}},
+				templateSheet(7),
+			},
+		}
+	})
+
+	cfg, err := GetSheetConfig("ssid", 7, srv)
+	if err != nil {
+		t.Fatalf("GetSheetConfig: %v", err)
+	}
+
+	if cfg.FrozenColumnCount != 1 || cfg.FrozenRowCount != 2 || cfg.TabColor == nil {
+		t.Errorf("properties = %+v", cfg)
+	}
+	if len(cfg.BandedRanges) != 1 || cfg.BasicFilter == nil || len(cfg.ConditionalFormats) != 1 || len(cfg.ProtectedRanges) != 1 {
+		t.Errorf("config = %+v", cfg)
+	}
+
+	if _, err := GetSheetConfig("ssid", 3, srv); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("err = %v, want ErrSheetNotFound", err)
+	}
+}
+
+func TestApplySheetConfig(t *testing.T) {
+	sheet := templateSheet(7)
+	cfg := &SheetConfig{
+		BandedRanges:       sheet.BandedRanges,
+		BasicFilter:        sheet.BasicFilter,
+		ConditionalFormats: sheet.ConditionalFormats,
+		ProtectedRanges:    sheet.ProtectedRanges,
+		TabColor:           sheet.Properties.TabColorStyle,
+	}
+
+	srv, api := newFakeService(t, nil)
+	if err := ApplySheetConfig("ssid", 9, cfg, srv); err != nil {
+		t.Fatalf("ApplySheetConfig: %v", err)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 5 {
+		t.Fatalf("got %d requests, want 5", len(requests))
+	}
+
+	props := requests[0].UpdateSheetProperties
+	if props == nil || props.Properties.SheetId != 9 || props.Properties.GridProperties.FrozenRowCount != 0 {
+		t.Errorf("properties request = %+v", props)
+	}
+
+	banding := requests[1].AddBanding
+	if banding == nil || banding.BandedRange.BandedRangeId != 0 || banding.BandedRange.Range.SheetId != 9 || banding.BandedRange.Range.EndRowIndex != 10 {
+		t.Errorf("banding request = %+v", banding)
+	}
+
+	filter := requests[2].SetBasicFilter
+	if filter == nil || filter.Filter.Range.SheetId != 9 || filter.Filter.Range.EndColumnIndex != 5 {
+		t.Errorf("filter request = %+v", filter)
+	}
+
+	rule := requests[3].AddConditionalFormatRule
+	if rule == nil || rule.Rule.Ranges[0].SheetId != 9 || rule.Rule.GradientRule == nil {
+		t.Errorf("conditional format request = %+v", rule)
+	}
+
+	protected := requests[4].AddProtectedRange
+	if protected == nil {
+		t.Fatalf("request 4 = %+v, want an AddProtectedRange", requests[4])
+	}
+	pr := protected.ProtectedRange
+	if pr.ProtectedRangeId != 0 || pr.RequestingUserCanEdit || pr.Range.SheetId != 9 || pr.UnprotectedRanges[0].SheetId != 9 {
+		t.Errorf("protected range = %+v", pr)
+	}
+
+	// The template's own ranges should be left untouched.
+	if sheet.BandedRanges[0].Range.SheetId != 7 || sheet.ProtectedRanges[0].UnprotectedRanges[0].SheetId != 7 {
+		t.Error("the template config was modified")
+	}
+}
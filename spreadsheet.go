@@ -0,0 +1,70 @@
+package rwsheets
+
+import (
+	"fmt"
+	"sort"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// NewTheme: Creates a new spreadsheet theme with the given font family and theme colors.
+//
+// colors should map the theme color types (TEXT, BACKGROUND, ACCENT1 through ACCENT6 and LINK)
+// to their colors. The API requires every theme color type to be set when updating the theme.
+func NewTheme(primaryFont string, colors map[string]*sheets.ColorStyle) *sheets.SpreadsheetTheme {
+	// Sort the color types so the theme is built in a consistent order.
+	var colorTypes []string
+	for colorType := range colors {
+		colorTypes = append(colorTypes, colorType)
+	}
+	sort.Strings(colorTypes)
+
+	var pairs []*sheets.ThemeColorPair
+	for _, colorType := range colorTypes {
+		pairs = append(pairs, &sheets.ThemeColorPair{
+			Color:     colors[colorType],
+			ColorType: colorType,
+		})
+	}
+
+	return &sheets.SpreadsheetTheme{
+		PrimaryFontFamily: primaryFont,
+		ThemeColors:       pairs,
+	}
+}
+
+// SetSpreadsheetTheme: Updates the spreadsheets theme.
+func SetSpreadsheetTheme(ssid string, theme *sheets.SpreadsheetTheme, srv *sheets.Service) error {
+	request := sheets.Request{
+		UpdateSpreadsheetProperties: &sheets.UpdateSpreadsheetPropertiesRequest{
+			Fields: "spreadsheetTheme",
+			Properties: &sheets.SpreadsheetProperties{
+				SpreadsheetTheme: theme,
+			},
+		},
+	}
+
+	_, err := batchUpdate(ssid, []*sheets.Request{&request}, srv)
+	return err
+}
+
+// CreateSpreadsheet: Creates a new spreadsheet with the given title and one sheet per sheet title,
+// returning the new spreadsheets ID. If no sheet titles are given the spreadsheet gets the default "Sheet1".
+func CreateSpreadsheet(title string, sheetTitles []string, srv *sheets.Service) (string, error) {
+	ss := sheets.Spreadsheet{
+		Properties: &sheets.SpreadsheetProperties{
+			Title: title,
+		},
+	}
+	for _, sheetTitle := range sheetTitles {
+		ss.Sheets = append(ss.Sheets, &sheets.Sheet{
+			Properties: &sheets.SheetProperties{Title: sheetTitle},
+		})
+	}
+
+	resp, err := srv.Spreadsheets.Create(&ss).Fields("spreadsheetId").Do()
+	if err != nil {
+		return "", fmt.Errorf("unable to create spreadsheet %s: %w", title, err)
+	}
+	return resp.SpreadsheetId, nil
+}
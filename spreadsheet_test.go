@@ -0,0 +1,91 @@
+package rwsheets
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestSetSpreadsheetTheme(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	theme := NewTheme("Roboto", map[string]*sheets.ColorStyle{
+		"TEXT":       BLACK_COLOR,
+		"BACKGROUND": Color(1, 1, 1, 1),
+		"ACCENT1":    Color(1, 0.5, 0, 0),
+	})
+	if err := SetSpreadsheetTheme("ssid", theme, srv); err != nil {
+		t.Fatalf("SetSpreadsheetTheme: %v", err)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 1 || requests[0].UpdateSpreadsheetProperties == nil {
+		t.Fatalf("requests = %+v, want a single UpdateSpreadsheetProperties", requests)
+	}
+
+	req := requests[0].UpdateSpreadsheetProperties
+	if req.Fields != "spreadsheetTheme" {
+		t.Errorf("fields = %q, want spreadsheetTheme", req.Fields)
+	}
+
+	got := req.Properties.SpreadsheetTheme
+	if got.PrimaryFontFamily != "Roboto" {
+		t.Errorf("font = %q, want Roboto", got.PrimaryFontFamily)
+	}
+
+	// The colors are sorted by type.
+	wantTypes := []string{"ACCENT1", "BACKGROUND", "TEXT"}
+	if len(got.ThemeColors) != len(wantTypes) {
+		t.Fatalf("got %d theme colors, want %d", len(got.ThemeColors), len(wantTypes))
+	}
+	for i, pair := range got.ThemeColors {
+		if pair.ColorType != wantTypes[i] || pair.Color == nil {
+			t.Errorf("theme color %d = %+v, want %s", i, pair, wantTypes[i])
+		}
+	}
+	if blue := got.ThemeColors[0].Color.RgbColor.Blue; blue != 0.5 {
+		t.Errorf("ACCENT1 blue = %v, want 0.5", blue)
+	}
+}
+
+func TestCreateSpreadsheet(t *testing.T) {
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusOK, &sheets.Spreadsheet{SpreadsheetId: "new-id"}
+	})
+
+	id, err := CreateSpreadsheet("Q3 Report", []string{"Summary", "Data"}, srv)
+	if err != nil {
+		t.Fatalf("CreateSpreadsheet: %v", err)
+	}
+	if id != "new-id" {
+		t.Errorf("id = %q, want new-id", id)
+	}
+
+	call := api.Calls()[0]
+	if call.Method != http.MethodPost || call.Path != "/v4/spreadsheets" {
+		t.Errorf("call = %s %s, want a create", call.Method, call.Path)
+	}
+
+	var ss sheets.Spreadsheet
+	if err := json.Unmarshal(call.Body, &ss); err != nil {
+		t.Fatal(err)
+	}
+	if ss.Properties.Title != "Q3 Report" {
+		t.Errorf("title = %q", ss.Properties.Title)
+	}
+	if len(ss.Sheets) != 2 || ss.Sheets[0].Properties.Title != "Summary" || ss.Sheets[1].Properties.Title != "Data" {
+		t.Errorf("sheets = %+v, want Summary and Data", ss.Sheets)
+	}
+}
+
+func TestCreateSpreadsheetError(t *testing.T) {
+	srv, _ := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusForbidden, apiError(http.StatusForbidden)
+	})
+
+	if _, err := CreateSpreadsheet("Report", nil, srv); err == nil {
+		t.Error("expected an error")
+	}
+}
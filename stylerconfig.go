@@ -0,0 +1,121 @@
+package rwsheets
+
+import (
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// StylerConfig: struct holding a stylers settings, to be used to store styling in a JSON config file.
+type StylerConfig struct {
+	FontBold            bool   `json:"fontBold,omitempty"`
+	Italic              bool   `json:"italic,omitempty"`
+	Underline           bool   `json:"underline,omitempty"`
+	Strikethrough       bool   `json:"strikethrough,omitempty"`
+	FontFamily          string `json:"fontFamily,omitempty"`
+	FontSize            int64  `json:"fontSize,omitempty"`
+	DatePattern         string `json:"datePattern,omitempty"`
+	NumberPattern       string `json:"numberPattern,omitempty"`
+	Decimals            *int   `json:"decimals,omitempty"`
+	PercentPattern      string `json:"percentPattern,omitempty"`
+	HorizontalAlignment string `json:"horizontalAlignment,omitempty"`
+	VerticalAlignment   string `json:"verticalAlignment,omitempty"`
+	NegativeStyle       string `json:"negativeStyle,omitempty"`
+	CurrencySymbol      string `json:"currencySymbol,omitempty"`
+	TextDirection       string `json:"textDirection,omitempty"`
+	WrapStrategy        string `json:"wrapStrategy,omitempty"`
+	TextRotationAngle   int64  `json:"textRotationAngle,omitempty"`
+	TextVertical        bool   `json:"textVertical,omitempty"`
+	BackgroundColor     string `json:"backgroundColor,omitempty"` // As "#RRGGBB". Alpha and theme colors aren't kept.
+	TextColor           string `json:"textColor,omitempty"`       // As "#RRGGBB". Alpha and theme colors aren't kept.
+}
+
+// Config: Returns the stylers settings as a StylerConfig.
+func (s *Styler) Config() StylerConfig {
+	decimals := s.decimals
+	cfg := StylerConfig{
+		FontBold:            s.fontBold,
+		Italic:              s.italic,
+		Underline:           s.underline,
+		Strikethrough:       s.strikethrough,
+		FontFamily:          s.fontFamily,
+		FontSize:            s.fontSize,
+		DatePattern:         s.datePattern,
+		NumberPattern:       s.numberPattern,
+		Decimals:            &decimals,
+		PercentPattern:      s.percentPattern,
+		HorizontalAlignment: s.horizontalAlignment,
+		VerticalAlignment:   s.verticalAlignment,
+		NegativeStyle:       s.negativeStyle,
+		CurrencySymbol:      s.currencySymbol,
+		TextDirection:       s.textDirection,
+		WrapStrategy:        s.wrapStrategy,
+		BackgroundColor:     styleHex(s.backgroundColor),
+		TextColor:           styleHex(s.textColor),
+	}
+	if s.textRotation != nil {
+		cfg.TextRotationAngle = s.textRotation.Angle
+		cfg.TextVertical = s.textRotation.Vertical
+	}
+	return cfg
+}
+
+// StylerFromConfig: Returns a new styler using the configs settings.
+// Any settings left empty in the config keep the NewStyler defaults.
+func StylerFromConfig(cfg StylerConfig) *Styler {
+	s := NewStyler().FontBold(cfg.FontBold).Italic(cfg.Italic).Underline(cfg.Underline).Strikethrough(cfg.Strikethrough)
+	if cfg.FontFamily != "" {
+		s.FontFamily(cfg.FontFamily)
+	}
+	if cfg.FontSize != 0 {
+		s.FontSize(cfg.FontSize)
+	}
+	if cfg.DatePattern != "" {
+		s.DatePattern(cfg.DatePattern)
+	}
+	if cfg.NumberPattern != "" {
+		s.NumberPattern(cfg.NumberPattern)
+	}
+	if cfg.Decimals != nil {
+		s.Decimals(*cfg.Decimals)
+	}
+	if cfg.PercentPattern != "" {
+		s.PercentPattern(cfg.PercentPattern)
+	}
+	if cfg.HorizontalAlignment != "" {
+		s.HorizontalAlignment(cfg.HorizontalAlignment)
+	}
+	if cfg.VerticalAlignment != "" {
+		s.VerticalAlignment(cfg.VerticalAlignment)
+	}
+	if cfg.NegativeStyle != "" {
+		s.NegativeStyle(cfg.NegativeStyle)
+	}
+	if cfg.CurrencySymbol != "" {
+		s.CurrencySymbol(cfg.CurrencySymbol)
+	}
+	if cfg.TextDirection != "" {
+		s.TextDirection(cfg.TextDirection)
+	}
+	if cfg.WrapStrategy != "" {
+		s.WrapStrategy(cfg.WrapStrategy)
+	}
+	if cfg.TextVertical {
+		s.TextRotationVertical(true)
+	} else if cfg.TextRotationAngle != 0 {
+		s.TextRotationAngle(cfg.TextRotationAngle)
+	}
+	if c, err := ColorHex(cfg.BackgroundColor); err == nil {
+		s.BackgroundColor(c)
+	}
+	if c, err := ColorHex(cfg.TextColor); err == nil {
+		s.TextColor(c)
+	}
+	return s
+}
+
+// styleHex: Returns the color style as "#RRGGBB", or "" if it is nil or a theme color.
+func styleHex(c *sheets.ColorStyle) string {
+	if c == nil {
+		return ""
+	}
+	return colorHex(c.RgbColor)
+}
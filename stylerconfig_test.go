@@ -0,0 +1,62 @@
+package rwsheets
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestStylerConfigRoundTrip(t *testing.T) {
+	s := NewStyler().
+		FontBold(true).
+		Italic(true).
+		Strikethrough(true).
+		FontFamily("Roboto").
+		FontSize(12).
+		DatePattern("yyyy-MM-dd").
+		NumberPattern("0.0").
+		Decimals(3).
+		PercentPattern("0%").
+		HorizontalAlignment("RIGHT").
+		VerticalAlignment("TOP").
+		NegativeStyle(NegativeRed).
+		CurrencySymbol("€").
+		TextDirection("RIGHT_TO_LEFT").
+		WrapStrategy("WRAP").
+		TextRotationAngle(45).
+		BackgroundColor(ColorRGB(1, 0.6, 0.2)).
+		TextColor(ColorRGB(0.8, 0, 0))
+
+	data, err := json.Marshal(s.Config())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg StylerConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	got := StylerFromConfig(cfg)
+	if !reflect.DeepEqual(got, s) {
+		t.Errorf("round trip = %+v, want %+v", got, s)
+	}
+	if bg := got.Config().BackgroundColor; bg != "#FF9933" {
+		t.Errorf("background color = %q, want #FF9933", bg)
+	}
+	if pattern := got.NumberFormat().Pattern; pattern != "0.0;[Red]-0.0" {
+		t.Errorf("number pattern = %q, want the negative style applied once", pattern)
+	}
+}
+
+func TestStylerFromConfigDefaults(t *testing.T) {
+	var cfg StylerConfig
+	if err := json.Unmarshal([]byte(`{"fontSize": 14, "negativeStyle": "bogus"}`), &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	want := NewStyler().FontSize(14)
+	if got := StylerFromConfig(cfg); !reflect.DeepEqual(got, want) {
+		t.Errorf("StylerFromConfig = %+v, want %+v", got, want)
+	}
+}
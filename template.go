@@ -0,0 +1,146 @@
+package rwsheets
+
+import (
+	"errors"
+	"fmt"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+const (
+	// EntryText columns accept any text.
+	EntryText = "TEXT"
+
+	// EntryDropdown columns only accept one of the columns options, picked from a dropdown.
+	EntryDropdown = "DROPDOWN"
+
+	// EntryNumberRange columns only accept numbers between the columns min and max, inclusive.
+	EntryNumberRange = "NUMBER_RANGE"
+
+	// EntryDate columns only accept valid dates.
+	EntryDate = "DATE"
+)
+
+var (
+	ErrEntryColumnType = errors.New("entry column type must be one of TEXT, DROPDOWN, NUMBER_RANGE or DATE")
+)
+
+// EntryColumn: struct to be used to describe a single column of a data entry template.
+type EntryColumn struct {
+	Header    string
+	Type      string   // One of EntryText, EntryDropdown, EntryNumberRange or EntryDate.
+	Options   []string // The values to choose from for EntryDropdown columns.
+	Min, Max  float64  // The allowed range for EntryNumberRange columns.
+	Protected bool     // If true, only the specs editors can edit the column, like for formula columns.
+}
+
+// EntryTemplateSpec: struct to be used to describe a data entry template for BuildEntryTemplate.
+type EntryTemplateSpec struct {
+	Columns []EntryColumn
+
+	// Optional. The number of entry rows below the header. If 0, the columns run to the end of the sheet.
+	Rows int64
+
+	// Optional. The stylers used for the header row and the entry rows.
+	// The header defaults to bold text and the body to the NewStyler defaults.
+	HeaderStyler *Styler
+	BodyStyler   *Styler
+
+	// Optional. Email addresses of the users allowed to edit protected columns.
+	Editors []string
+}
+
+// BuildEntryTemplate: Sets up the sheet as a data entry form in a single batch update.
+// The header row is written to the first row, and each columns entry rows below it get the validation and
+// number format for the columns type. Protected columns are protected from the header down.
+func BuildEntryTemplate(ssid string, gid int64, spec EntryTemplateSpec, srv *sheets.Service) error {
+	requests, err := entryTemplateRequests(gid, spec)
+	if err != nil {
+		return err
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+
+	_, err = batchUpdate(ssid, requests, srv)
+	return err
+}
+
+// entryTemplateRequests: Creates the requests for setting up the entry template described by the spec.
+func entryTemplateRequests(gid int64, spec EntryTemplateSpec) ([]*sheets.Request, error) {
+	if len(spec.Columns) == 0 {
+		return nil, nil
+	}
+
+	header := spec.HeaderStyler
+	if header == nil {
+		header = NewStyler().FontBold(true)
+	}
+	body := spec.BodyStyler
+	if body == nil {
+		body = NewStyler()
+	}
+
+	var endRow int64
+	if spec.Rows > 0 {
+		endRow = 1 + spec.Rows
+	}
+
+	var headers []string
+	for _, column := range spec.Columns {
+		headers = append(headers, column.Header)
+	}
+	width := int64(len(spec.Columns))
+	requests := []*sheets.Request{
+		updateCellsRequest(width, gid, 0, 0, header.CreateHeaderRow(headers, nil), "*"),
+	}
+
+	for i, column := range spec.Columns {
+		col := int64(i)
+		entries := &sheets.GridRange{
+			EndColumnIndex:   col + 1,
+			EndRowIndex:      endRow,
+			SheetId:          gid,
+			StartColumnIndex: col,
+			StartRowIndex:    1,
+		}
+
+		var rule *sheets.DataValidationRule
+		var nf *sheets.NumberFormat
+		switch column.Type {
+		case EntryText, "":
+		case EntryDropdown:
+			rule = ListValidation(column.Options, nil)
+		case EntryNumberRange:
+			rule = NumberRangeValidation(column.Min, column.Max, nil)
+			nf = body.NumberFormat()
+		case EntryDate:
+			rule = validationRule(&sheets.BooleanCondition{Type: "DATE_IS_VALID"}, nil)
+			nf = body.DateFormat()
+		default:
+			return nil, fmt.Errorf("%w: column %q has type %q", ErrEntryColumnType, column.Header, column.Type)
+		}
+
+		requests = append(requests, repeatFormatRequest(entries, body.cellFormat(nf, nil)))
+		if rule != nil {
+			requests = append(requests, &sheets.Request{
+				SetDataValidation: &sheets.SetDataValidationRequest{
+					Range: entries,
+					Rule:  rule,
+				},
+			})
+		}
+
+		if column.Protected {
+			protected := *entries
+			protected.StartRowIndex = 0
+			requests = append(requests, protectedRangeRequest(ProtectSpec{
+				Range:       &protected,
+				Description: column.Header,
+				Editors:     spec.Editors,
+			}))
+		}
+	}
+
+	return requests, nil
+}
@@ -0,0 +1,86 @@
+package rwsheets
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBuildEntryTemplate(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	spec := EntryTemplateSpec{
+		Columns: []EntryColumn{
+			{Header: "Name", Type: EntryText},
+			{Header: "Status", Type: EntryDropdown, Options: []string{"Open", "Closed"}},
+			{Header: "Score", Type: EntryNumberRange, Min: 0, Max: 10},
+			{Header: "Due", Type: EntryDate},
+			{Header: "Total", Protected: true},
+		},
+		Rows:    100,
+		Editors: []string{"owner@example.com"},
+	}
+	if err := BuildEntryTemplate("ssid", 6, spec, srv); err != nil {
+		t.Fatalf("BuildEntryTemplate: %v", err)
+	}
+
+	requests := api.Requests()
+	var header, formats, validations, protections int
+	for _, req := range requests {
+		switch {
+		case req.UpdateCells != nil:
+			header++
+			if got := gridValues(req.UpdateCells.Rows); got != "Name,Status,Score,Due,Total" {
+				t.Errorf("header = %s", got)
+			}
+			if !req.UpdateCells.Rows[0].Values[0].UserEnteredFormat.TextFormat.Bold {
+				t.Error("header isn't bold")
+			}
+		case req.RepeatCell != nil:
+			formats++
+			if r := req.RepeatCell.Range; r.SheetId != 6 || r.StartRowIndex != 1 || r.EndRowIndex != 101 {
+				t.Errorf("format range = %+v, want the 100 rows below the header", r)
+			}
+		case req.SetDataValidation != nil:
+			validations++
+		case req.AddProtectedRange != nil:
+			protections++
+			pr := req.AddProtectedRange.ProtectedRange
+			if got := gridRangeToA1("", pr.Range); got != "E1:E101" || pr.Editors.Users[0] != "owner@example.com" {
+				t.Errorf("protected range = %s with editors %+v, want E1:E101", got, pr.Editors)
+			}
+		default:
+			t.Errorf("unexpected request %+v", req)
+		}
+	}
+
+	if header != 1 || formats != 5 || validations != 3 || protections != 1 {
+		t.Errorf("got %d header, %d format, %d validation and %d protection requests, want 1, 5, 3 and 1",
+			header, formats, validations, protections)
+	}
+
+	// Each validation is for its own column type.
+	var conditions []string
+	for _, req := range requests {
+		if req.SetDataValidation != nil {
+			conditions = append(conditions, req.SetDataValidation.Rule.Condition.Type)
+		}
+	}
+	if len(conditions) != 3 || conditions[0] != "ONE_OF_LIST" || conditions[1] != "NUMBER_BETWEEN" || conditions[2] != "DATE_IS_VALID" {
+		t.Errorf("conditions = %v", conditions)
+	}
+}
+
+func TestBuildEntryTemplateInvalidType(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	spec := EntryTemplateSpec{Columns: []EntryColumn{{Header: "Name", Type: "EMAIL"}}}
+	if err := BuildEntryTemplate("ssid", 0, spec, srv); !errors.Is(err, ErrEntryColumnType) {
+		t.Errorf("err = %v, want ErrEntryColumnType", err)
+	}
+	if err := BuildEntryTemplate("ssid", 0, EntryTemplateSpec{}, srv); err != nil {
+		t.Errorf("empty spec err = %v", err)
+	}
+	if len(api.Calls()) != 0 {
+		t.Errorf("got %d calls, want none", len(api.Calls()))
+	}
+}
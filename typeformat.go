@@ -0,0 +1,44 @@
+package rwsheets
+
+import (
+	"reflect"
+	"sync"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+var (
+	typeFormatsMu sync.RWMutex
+	typeFormats   = map[reflect.Type]*sheets.NumberFormat{}
+)
+
+// RegisterTypeFormat: Sets the number format used for values of the given type when building cells from Go values,
+// like the rows of BuildSectionedRows. Registering a type that already has a format replaces it.
+//
+// Types without a registered format use the stylers defaults: time.Time values use its date format, numbers
+// use its number format and bools are written as checkboxes. Registering time.Time or a number type, including
+// custom ones like `type Money int64`, overrides the default. Bools and strings aren't number formatted.
+func RegisterTypeFormat(t reflect.Type, nf *sheets.NumberFormat) {
+	typeFormatsMu.Lock()
+	defer typeFormatsMu.Unlock()
+
+	typeFormats[t] = nf
+}
+
+// TypeFormat: Returns the number format registered for the type, or nil if there isn't one.
+// Pointer types use the format registered for the type they point to.
+func TypeFormat(t reflect.Type) *sheets.NumberFormat {
+	typeFormatsMu.RLock()
+	defer typeFormatsMu.RUnlock()
+
+	for t != nil {
+		if nf, ok := typeFormats[t]; ok {
+			return nf
+		}
+		if t.Kind() != reflect.Pointer {
+			break
+		}
+		t = t.Elem()
+	}
+	return nil
+}
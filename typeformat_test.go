@@ -0,0 +1,94 @@
+package rwsheets
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// money is a custom type registered with its own number format.
+type money int64
+
+// registerTypeFormat: Registers the format for the type, removing it again when the test ends.
+func registerTypeFormat(t *testing.T, typ reflect.Type, nf *sheets.NumberFormat) {
+	t.Helper()
+
+	RegisterTypeFormat(typ, nf)
+	t.Cleanup(func() {
+		typeFormatsMu.Lock()
+		delete(typeFormats, typ)
+		typeFormatsMu.Unlock()
+	})
+}
+
+func TestRegisterTypeFormat(t *testing.T) {
+	moneyType := reflect.TypeOf(money(0))
+	if nf := TypeFormat(moneyType); nf != nil {
+		t.Fatalf("format = %+v before registering, want nil", nf)
+	}
+
+	nf := NumberFormat(`"$"#,##0`)
+	registerTypeFormat(t, moneyType, nf)
+
+	if got := TypeFormat(moneyType); got != nf {
+		t.Errorf("format = %+v, want %+v", got, nf)
+	}
+	if got := TypeFormat(reflect.TypeOf(new(money))); got != nf {
+		t.Errorf("pointer format = %+v, want %+v", got, nf)
+	}
+
+	// Cells built from the values use the registered format.
+	s := NewStyler()
+	amount := money(1250)
+	for _, value := range []interface{}{amount, &amount} {
+		cell := s.valueCell(value, nil)
+		if got := cell.UserEnteredFormat.NumberFormat; got != nf {
+			t.Errorf("%T format = %+v, want the registered format", value, got)
+		}
+		if v := cell.UserEnteredValue; v.NumberValue == nil || *v.NumberValue != 1250 {
+			t.Errorf("%T value = %+v, want 1250", value, v)
+		}
+	}
+
+	// Other number types keep the stylers format.
+	if got := s.valueCell(int64(1250), nil).UserEnteredFormat.NumberFormat; !reflect.DeepEqual(got, s.NumberFormat()) {
+		t.Errorf("int64 format = %+v, want the stylers number format", got)
+	}
+}
+
+func TestTypeFormatDefaults(t *testing.T) {
+	s := NewStyler().DatePattern("yyyy-MM-dd").Decimals(1)
+
+	date := s.valueCell(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), nil)
+	if nf := date.UserEnteredFormat.NumberFormat; nf.Type != "DATE" || nf.Pattern != "yyyy-MM-dd" {
+		t.Errorf("time.Time format = %+v, want the stylers date format", nf)
+	}
+	if v := date.UserEnteredValue; *v.NumberValue != 45292.5 {
+		t.Errorf("time.Time value = %v, want 45292.5", *v.NumberValue)
+	}
+
+	if nf := s.valueCell(2.5, nil).UserEnteredFormat.NumberFormat; !reflect.DeepEqual(nf, s.NumberFormat()) {
+		t.Errorf("float64 format = %+v, want the stylers number format", nf)
+	}
+
+	check := s.valueCell(true, nil)
+	if rule := check.DataValidation; rule == nil || rule.Condition.Type != "BOOLEAN" {
+		t.Errorf("bool validation = %+v, want a checkbox", rule)
+	}
+	if nf := check.UserEnteredFormat.NumberFormat; nf != nil {
+		t.Errorf("bool format = %+v, want none", nf)
+	}
+
+	// The defaults can be overridden.
+	percent := &sheets.NumberFormat{Type: "PERCENT", Pattern: "0%"}
+	registerTypeFormat(t, reflect.TypeOf(float64(0)), percent)
+	if nf := s.valueCell(0.25, nil).UserEnteredFormat.NumberFormat; nf != percent {
+		t.Errorf("float64 format = %+v, want the registered format", nf)
+	}
+
+	if cell := s.valueCell((*money)(nil), nil); cell.UserEnteredValue != nil {
+		t.Errorf("nil pointer value = %+v, want an empty cell", cell.UserEnteredValue)
+	}
+}
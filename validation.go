@@ -0,0 +1,160 @@
+package rwsheets
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+var (
+	ErrCustomFormula = errors.New("custom formula validations need a formula starting with =")
+)
+
+// SetDataValidation: Sets the data validation rule for every cell in the given range.
+func SetDataValidation(ssid string, gr *sheets.GridRange, rule *sheets.DataValidationRule, srv *sheets.Service) error {
+	request := sheets.Request{
+		SetDataValidation: &sheets.SetDataValidationRequest{
+			Range: gr,
+			Rule:  rule,
+		},
+	}
+
+	_, err := batchUpdate(ssid, []*sheets.Request{&request}, srv)
+	return err
+}
+
+// ClearDataValidation: Removes the data validation from every cell in the given range.
+func ClearDataValidation(ssid string, gr *sheets.GridRange, srv *sheets.Service) error {
+	// A SetDataValidationRequest without a rule clears the validation over the range.
+	return SetDataValidation(ssid, gr, nil, srv)
+}
+
+// GetDataValidations: Retrieve the data validation rules in the range as a map of A1 cell references to rules.
+// Cells without data validation are left out of the map.
+//
+// Only the "sheets(data(startRow,startColumn,rowData(values(dataValidation))))" grid fields are requested.
+func GetDataValidations(ssid, readRange string, srv *sheets.Service) (map[string]*sheets.DataValidationRule, error) {
+	rules := make(map[string]*sheets.DataValidationRule)
+
+	ss, err := srv.Spreadsheets.Get(ssid).Ranges(readRange).
+		Fields("sheets(data(startRow,startColumn,rowData(values(dataValidation))))").Do()
+	if err != nil {
+		return rules, err
+	}
+
+	grid, err := firstGrid(ss)
+	if err != nil {
+		return rules, err
+	}
+
+	for i, row := range grid.RowData {
+		for j, cell := range row.Values {
+			if cell == nil || cell.DataValidation == nil {
+				continue
+			}
+
+			ref := CellRef(grid.StartRow+int64(i), grid.StartColumn+int64(j))
+			rules[ref] = cell.DataValidation
+		}
+	}
+
+	return rules, nil
+}
+
+// ValidationConf: struct to be used to set the optional behaviour of data validation rules.
+// A nil ValidationConf rejects invalid input, shows no input message and shows dropdowns for lists.
+type ValidationConf struct {
+	InputMessage string // Optional. Shown to the user when they select the cell.
+	Lenient      bool   // If true, invalid input is allowed with a warning instead of being rejected.
+	HideDropdown bool   // If true, list validations will not show a dropdown in the cell.
+}
+
+// validationRule: Creates a new data validation rule for the condition using the configuration.
+func validationRule(condition *sheets.BooleanCondition, conf *ValidationConf) *sheets.DataValidationRule {
+	if conf == nil {
+		conf = &ValidationConf{}
+	}
+
+	return &sheets.DataValidationRule{
+		Condition:    condition,
+		InputMessage: conf.InputMessage,
+		Strict:       !conf.Lenient,
+	}
+}
+
+// CheckBoxValidation: Creates a data validation rule that displays cells as checkboxes.
+func CheckBoxValidation(conf *ValidationConf) *sheets.DataValidationRule {
+	return validationRule(&sheets.BooleanCondition{Type: "BOOLEAN"}, conf)
+}
+
+// ListValidation: Creates a data validation rule that only allows one of the given values.
+func ListValidation(values []string, conf *ValidationConf) *sheets.DataValidationRule {
+	var conditionValues []*sheets.ConditionValue
+	for _, value := range values {
+		conditionValues = append(conditionValues, &sheets.ConditionValue{
+			UserEnteredValue: value,
+		})
+	}
+
+	rule := validationRule(&sheets.BooleanCondition{
+		Type:   "ONE_OF_LIST",
+		Values: conditionValues,
+	}, conf)
+	rule.ShowCustomUi = conf == nil || !conf.HideDropdown
+	return rule
+}
+
+// NumberRangeValidation: Creates a data validation rule that only allows numbers between min and max, inclusive.
+func NumberRangeValidation(min, max float64, conf *ValidationConf) *sheets.DataValidationRule {
+	return validationRule(&sheets.BooleanCondition{
+		Type: "NUMBER_BETWEEN",
+		Values: []*sheets.ConditionValue{
+			{UserEnteredValue: strconv.FormatFloat(min, 'f', -1, 64)},
+			{UserEnteredValue: strconv.FormatFloat(max, 'f', -1, 64)},
+		},
+	}, conf)
+}
+
+// DateAfterTodayValidation: Creates a data validation rule that only allows dates after today.
+// If strict is false, earlier dates are allowed with a warning.
+func DateAfterTodayValidation(strict bool) *sheets.DataValidationRule {
+	return validationRule(&sheets.BooleanCondition{
+		Type: "DATE_AFTER",
+		Values: []*sheets.ConditionValue{
+			{RelativeDate: "TODAY"},
+		},
+	}, &ValidationConf{Lenient: !strict})
+}
+
+// DateBetweenValidation: Creates a data validation rule that only allows dates between min and max, inclusive.
+// The dates are sent as serial numbers, using their wall clock time. If strict is false, other dates are allowed with a warning.
+func DateBetweenValidation(min, max time.Time, strict bool) *sheets.DataValidationRule {
+	return validationRule(&sheets.BooleanCondition{
+		Type: "DATE_BETWEEN",
+		Values: []*sheets.ConditionValue{
+			{UserEnteredValue: strconv.FormatFloat(timeToSerial(min), 'f', -1, 64)},
+			{UserEnteredValue: strconv.FormatFloat(timeToSerial(max), 'f', -1, 64)},
+		},
+	}, &ValidationConf{Lenient: !strict})
+}
+
+// CustomFormulaValidation: Creates a data validation rule that only allows input while the formula is true,
+// like "=$A2<>\"\"" to only allow input once column A of the row is set. Relative references in the formula
+// are adjusted for each cell in the range, the same as when entered in the sheet. If strict is false,
+// input is allowed with a warning while the formula is false.
+func CustomFormulaValidation(formula string, strict bool) (*sheets.DataValidationRule, error) {
+	if !strings.HasPrefix(formula, "=") {
+		return nil, fmt.Errorf("%w: %q", ErrCustomFormula, formula)
+	}
+
+	return validationRule(&sheets.BooleanCondition{
+		Type: "CUSTOM_FORMULA",
+		Values: []*sheets.ConditionValue{
+			{UserEnteredValue: formula},
+		},
+	}, &ValidationConf{Lenient: !strict}), nil
+}
@@ -0,0 +1,198 @@
+package rwsheets
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestClearDataValidation(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	gr := &sheets.GridRange{SheetId: 3, StartRowIndex: 1, EndRowIndex: 100, StartColumnIndex: 2, EndColumnIndex: 3}
+	if err := ClearDataValidation("ssid", gr, srv); err != nil {
+		t.Fatalf("ClearDataValidation: %v", err)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 1 || requests[0].SetDataValidation == nil {
+		t.Fatalf("requests = %+v, want a single SetDataValidation", requests)
+	}
+
+	req := requests[0].SetDataValidation
+	if req.Rule != nil {
+		t.Errorf("rule = %+v, want nil to remove the validation", req.Rule)
+	}
+	if got := *req.Range; got.SheetId != 3 || got.StartRowIndex != 1 || got.EndRowIndex != 100 || got.StartColumnIndex != 2 || got.EndColumnIndex != 3 {
+		t.Errorf("range = %+v, want %+v", got, *gr)
+	}
+}
+
+func TestGetDataValidations(t *testing.T) {
+	dropdown := ListValidation([]string{"yes", "no"}, nil)
+	checkbox := CheckBoxValidation(nil)
+
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		// B3:C4, with the dropdown in B3 and the checkbox in C4.
+		return http.StatusOK, gridResponse(2, 1,
+			&sheets.RowData{Values: []*sheets.CellData{{DataValidation: dropdown}, {}}},
+			&sheets.RowData{Values: []*sheets.CellData{{}, {DataValidation: checkbox}}},
+		)
+	})
+
+	rules, err := GetDataValidations("ssid", "Form!B3:C4", srv)
+	if err != nil {
+		t.Fatalf("GetDataValidations: %v", err)
+	}
+
+	if len(rules) != 2 {
+		t.Fatalf("rules = %v, want 2 rules", rules)
+	}
+	if rule := rules["B3"]; rule == nil || rule.Condition.Type != "ONE_OF_LIST" || len(rule.Condition.Values) != 2 {
+		t.Errorf("B3 = %+v, want the dropdown", rule)
+	}
+	if rule := rules["C4"]; rule == nil || rule.Condition.Type != "BOOLEAN" {
+		t.Errorf("C4 = %+v, want the checkbox", rule)
+	}
+
+	call := api.Calls()[0]
+	if got := call.Query.Get("fields"); got != "sheets(data(startRow,startColumn,rowData(values(dataValidation))))" {
+		t.Errorf("fields = %q", got)
+	}
+	if got := call.Query.Get("ranges"); got != "Form!B3:C4" {
+		t.Errorf("ranges = %q", got)
+	}
+}
+
+func TestValidationConf(t *testing.T) {
+	// The defaults reject invalid input and show the dropdown.
+	rule := ListValidation([]string{"a", "b"}, nil)
+	if !rule.Strict || !rule.ShowCustomUi || rule.InputMessage != "" {
+		t.Errorf("default rule = %+v", rule)
+	}
+
+	conf := &ValidationConf{InputMessage: "Pick one", Lenient: true, HideDropdown: true}
+	rule = ListValidation([]string{"a", "b"}, conf)
+	if rule.Strict || rule.ShowCustomUi || rule.InputMessage != "Pick one" {
+		t.Errorf("configured rule = %+v", rule)
+	}
+
+	rule = NumberRangeValidation(1, 10.5, &ValidationConf{InputMessage: "1 to 10.5"})
+	if !rule.Strict || rule.InputMessage != "1 to 10.5" {
+		t.Errorf("number rule = %+v", rule)
+	}
+	if c := rule.Condition; c.Type != "NUMBER_BETWEEN" || c.Values[0].UserEnteredValue != "1" || c.Values[1].UserEnteredValue != "10.5" {
+		t.Errorf("number condition = %+v", c)
+	}
+
+	rule = CheckBoxValidation(&ValidationConf{Lenient: true})
+	if rule.Strict || rule.Condition.Type != "BOOLEAN" {
+		t.Errorf("checkbox rule = %+v", rule)
+	}
+}
+
+func TestCheckBoxCellValidation(t *testing.T) {
+	cell := NewStyler().CheckBoxCell(true, nil)
+	if rule := cell.DataValidation; rule == nil || rule.Condition.Type != "BOOLEAN" || !rule.Strict {
+		t.Errorf("checkbox validation = %+v", rule)
+	}
+}
+
+func TestDateValidation(t *testing.T) {
+	rule := DateAfterTodayValidation(true)
+	if c := rule.Condition; c.Type != "DATE_AFTER" || len(c.Values) != 1 || c.Values[0].RelativeDate != "TODAY" {
+		t.Errorf("after today condition = %+v", c)
+	}
+	if !rule.Strict {
+		t.Error("strict rule is lenient")
+	}
+	if DateAfterTodayValidation(false).Strict {
+		t.Error("lenient rule is strict")
+	}
+
+	// The serial values count days from 1899-12-30, using the wall clock time.
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	min := time.Date(2024, 1, 1, 0, 0, 0, 0, loc)
+	max := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	rule = DateBetweenValidation(min, max, true)
+	c := rule.Condition
+	if c.Type != "DATE_BETWEEN" || len(c.Values) != 2 {
+		t.Fatalf("between condition = %+v", c)
+	}
+	if got := c.Values[0].UserEnteredValue; got != "45292" {
+		t.Errorf("min = %s, want 45292", got)
+	}
+	if got := c.Values[1].UserEnteredValue; got != "45322.5" {
+		t.Errorf("max = %s, want 45322.5", got)
+	}
+}
+
+func TestDateValidationCell(t *testing.T) {
+	rule := DateAfterTodayValidation(true)
+	cell := NewStyler().DateValidationCell("2024-01-01", "2006-01-02", rule, nil)
+
+	if cell.DataValidation != rule {
+		t.Errorf("validation = %+v, want the rule", cell.DataValidation)
+	}
+	if v := cell.UserEnteredValue; v == nil || v.NumberValue == nil || *v.NumberValue != 45292 {
+		t.Errorf("value = %+v, want the serial date", v)
+	}
+}
+
+func TestCustomFormulaValidation(t *testing.T) {
+	rule, err := CustomFormulaValidation(`=$A2<>""`, true)
+	if err != nil {
+		t.Fatalf("CustomFormulaValidation: %v", err)
+	}
+	c := rule.Condition
+	if c.Type != "CUSTOM_FORMULA" || len(c.Values) != 1 || c.Values[0].UserEnteredValue != `=$A2<>""` {
+		t.Errorf("condition = %+v, want the custom formula", c)
+	}
+	if !rule.Strict {
+		t.Error("strict rule is lenient")
+	}
+
+	rule, err = CustomFormulaValidation("=B2", false)
+	if err != nil || rule.Strict {
+		t.Errorf("lenient rule = %+v, %v", rule, err)
+	}
+
+	for _, formula := range []string{"", "$A2<>\"\"", " =B2"} {
+		if _, err := CustomFormulaValidation(formula, true); !errors.Is(err, ErrCustomFormula) {
+			t.Errorf("CustomFormulaValidation(%q) err = %v, want ErrCustomFormula", formula, err)
+		}
+	}
+}
+
+func TestConditionalCheckBoxCell(t *testing.T) {
+	cell, err := NewStyler().ConditionalCheckBoxCell(true, `=$A2<>""`, true, nil)
+	if err != nil {
+		t.Fatalf("ConditionalCheckBoxCell: %v", err)
+	}
+	if rule := cell.DataValidation; rule == nil || rule.Condition.Type != "CUSTOM_FORMULA" || rule.Condition.Values[0].UserEnteredValue != `=$A2<>""` || !rule.Strict {
+		t.Errorf("validation = %+v, want the strict custom formula", rule)
+	}
+	if v := cell.UserEnteredValue; v == nil || v.BoolValue == nil || !*v.BoolValue {
+		t.Errorf("value = %+v, want true", v)
+	}
+	if cell.UserEnteredFormat == nil {
+		t.Error("the stylers formatting wasn't used")
+	}
+
+	// False is still sent as a boolean.
+	cell, err = NewStyler().ConditionalCheckBoxCell(false, "=$A2", false, nil)
+	if err != nil {
+		t.Fatalf("ConditionalCheckBoxCell: %v", err)
+	}
+	if v := cell.UserEnteredValue; v.BoolValue == nil || *v.BoolValue || cell.DataValidation.Strict {
+		t.Errorf("cell = %+v, want a lenient false cell", cell)
+	}
+
+	if _, err := NewStyler().ConditionalCheckBoxCell(true, "A2", true, nil); !errors.Is(err, ErrCustomFormula) {
+		t.Errorf("err = %v, want ErrCustomFormula", err)
+	}
+}
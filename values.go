@@ -0,0 +1,137 @@
+package rwsheets
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+const (
+	// RenderFormatted reads values as they are displayed in the sheet.
+	RenderFormatted = "FORMATTED_VALUE"
+
+	// RenderUnformatted reads values without their number format, so numbers are returned as numbers.
+	RenderUnformatted = "UNFORMATTED_VALUE"
+
+	// RenderFormula reads formulas instead of their results.
+	RenderFormula = "FORMULA"
+)
+
+var (
+	ErrBatchClear   = errors.New("not all ranges were cleared")
+	ErrRenderOption = errors.New("invalid value render option")
+)
+
+// ClearRange: Clears the values of the A1 range, keeping formatting, data validation and other cell properties.
+// Use ClearFormatting to reset the formatting instead.
+func ClearRange(ssid, a1Range string, srv *sheets.Service) error {
+	if _, err := srv.Spreadsheets.Values.Clear(ssid, a1Range, &sheets.ClearValuesRequest{}).Do(); err != nil {
+		return fmt.Errorf("unable to clear range %s: %w", a1Range, err)
+	}
+	return nil
+}
+
+// BatchClear: Clears the values of all the given A1 ranges in a single request.
+// Formatting, data validation and other cell properties are kept.
+func BatchClear(ssid string, ranges []string, srv *sheets.Service) error {
+	req := sheets.BatchClearValuesRequest{
+		Ranges: ranges,
+	}
+
+	resp, err := srv.Spreadsheets.Values.BatchClear(ssid, &req).Do()
+	if err != nil {
+		return fmt.Errorf("unable to clear ranges %s: %w", strings.Join(ranges, ", "), err)
+	}
+
+	if len(resp.ClearedRanges) < len(ranges) {
+		return fmt.Errorf("%w: requested %s, cleared %s", ErrBatchClear,
+			strings.Join(ranges, ", "), strings.Join(resp.ClearedRanges, ", "))
+	}
+
+	return nil
+}
+
+// GetValues: Retrieve the values of the A1 range, like "Sheet1!A1:D10", as they are displayed.
+// This is much lighter than GetSheetData as only the values are returned, without any grid data.
+// Returns ErrNoData if the range is empty.
+func GetValues(ssid, a1Range string, srv *sheets.Service) ([][]interface{}, error) {
+	return GetValuesRendered(ssid, a1Range, "", srv)
+}
+
+// GetValuesRendered: Retrieve the values of the A1 range rendered with the given option, one of
+// RenderFormatted, RenderUnformatted or RenderFormula. An empty option uses RenderFormatted.
+// Reading with RenderFormula returns the formulas themselves, so they can be copied to another sheet.
+// Returns ErrNoData if the range is empty.
+func GetValuesRendered(ssid, a1Range, renderOption string, srv *sheets.Service) ([][]interface{}, error) {
+	call := srv.Spreadsheets.Values.Get(ssid, a1Range)
+	switch renderOption {
+	case "":
+	case RenderFormatted, RenderUnformatted, RenderFormula:
+		call = call.ValueRenderOption(renderOption)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrRenderOption, renderOption)
+	}
+
+	resp, err := call.Do()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get values of range %s: %w", a1Range, err)
+	}
+
+	if len(resp.Values) == 0 {
+		return nil, ErrNoData
+	}
+	return resp.Values, nil
+}
+
+// BatchGetValues: Retrieve the values of all the given A1 ranges in a single request.
+// The returned map is keyed by the requested range strings. Every requested range is included,
+// with ranges that have no values mapped to an empty slice.
+func BatchGetValues(ssid string, ranges []string, srv *sheets.Service) (map[string][][]interface{}, error) {
+	values := make(map[string][][]interface{})
+
+	resp, err := srv.Spreadsheets.Values.BatchGet(ssid).Ranges(ranges...).Do()
+	if err != nil {
+		return values, err
+	}
+
+	// The value ranges are returned in the same order they were requested,
+	// but the API normalizes the range strings so they can't be used to match them up.
+	for i, rng := range ranges {
+		values[rng] = [][]interface{}{}
+		if i < len(resp.ValueRanges) && resp.ValueRanges[i].Values != nil {
+			values[rng] = resp.ValueRanges[i].Values
+		}
+	}
+
+	return values, nil
+}
+
+// SafeAppend: Appends the values after the table found in the range, retrying conflicts and
+// rate limit errors with the DefaultRetryPolicy. Share a Client and use (*Client).SafeAppend
+// to also run appends to the same spreadsheet one at a time.
+func SafeAppend(ssid, a1Range string, values [][]interface{}, srv *sheets.Service) (*sheets.AppendValuesResponse, error) {
+	return NewClient(srv, WithRetryPolicy(DefaultRetryPolicy)).SafeAppend(ssid, a1Range, values)
+}
+
+// AppendValues: Appends the values after the table found in the A1 range and returns the A1 range,
+// like "Log!A11:C12", the values were written to. Use it to format or link to the appended rows.
+// The values are parsed as if typed into the sheet, so "=SUM(A1:A2)" becomes a formula.
+// Returns ErrNoData if the API doesn't report where the values were written.
+func AppendValues(ssid, a1Range string, values [][]interface{}, srv *sheets.Service) (string, error) {
+	vr := sheets.ValueRange{
+		Values: values,
+	}
+
+	resp, err := srv.Spreadsheets.Values.Append(ssid, a1Range, &vr).
+		InsertDataOption("INSERT_ROWS").ValueInputOption("USER_ENTERED").Do()
+	if err != nil {
+		return "", fmt.Errorf("unable to append values to range %s: %w", a1Range, err)
+	}
+
+	if resp.Updates == nil || resp.Updates.UpdatedRange == "" {
+		return "", ErrNoData
+	}
+	return resp.Updates.UpdatedRange, nil
+}
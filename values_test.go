@@ -0,0 +1,271 @@
+package rwsheets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestBatchClear(t *testing.T) {
+	ranges := []string{"A1:B2", "'My Sheet'!C:C", "Totals"}
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusOK, &sheets.BatchClearValuesResponse{ClearedRanges: ranges}
+	})
+
+	if err := BatchClear("ssid", ranges, srv); err != nil {
+		t.Fatalf("BatchClear: %v", err)
+	}
+
+	calls := api.Calls()
+	if len(calls) != 1 || !strings.HasSuffix(calls[0].Path, "/values:batchClear") {
+		t.Fatalf("calls = %+v, want a single batchClear", calls)
+	}
+
+	var req sheets.BatchClearValuesRequest
+	if err := json.Unmarshal(calls[0].Body, &req); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(req.Ranges, "|") != strings.Join(ranges, "|") {
+		t.Errorf("ranges = %v, want %v", req.Ranges, ranges)
+	}
+}
+
+func TestBatchClearPartial(t *testing.T) {
+	srv, _ := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusOK, &sheets.BatchClearValuesResponse{ClearedRanges: []string{"A1:B2"}}
+	})
+
+	err := BatchClear("ssid", []string{"A1:B2", "C1:D2"}, srv)
+	if !errors.Is(err, ErrBatchClear) || !strings.Contains(err.Error(), "C1:D2") {
+		t.Errorf("err = %v, want ErrBatchClear listing the requested ranges", err)
+	}
+}
+
+func TestBatchGetValues(t *testing.T) {
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		// The API returns the ranges normalized, so they shouldn't be used as the keys.
+		return http.StatusOK, &sheets.BatchGetValuesResponse{
+			ValueRanges: []*sheets.ValueRange{
+				{Range: "Sheet1!A1:B1", Values: [][]interface{}{{"a", "b"}}},
+				{Range: "Totals!C5", Values: [][]interface{}{{"42"}}},
+			},
+		}
+	})
+
+	ranges := []string{"A1:B1", "Totals!C5"}
+	values, err := BatchGetValues("ssid", ranges, srv)
+	if err != nil {
+		t.Fatalf("BatchGetValues: %v", err)
+	}
+
+	calls := api.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("got %d calls, want 1", len(calls))
+	}
+	if got := calls[0].Query["ranges"]; strings.Join(got, "|") != strings.Join(ranges, "|") {
+		t.Errorf("requested ranges = %v, want %v", got, ranges)
+	}
+
+	if got := values["A1:B1"]; len(got) != 1 || got[0][1] != "b" {
+		t.Errorf("A1:B1 = %v", got)
+	}
+	if got := values["Totals!C5"]; len(got) != 1 || got[0][0] != "42" {
+		t.Errorf("Totals!C5 = %v", got)
+	}
+}
+
+func TestGetValues(t *testing.T) {
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusOK, &sheets.ValueRange{
+			Range:  "Sheet1!A1:B2",
+			Values: [][]interface{}{{"name", "total"}, {"a", "12"}},
+		}
+	})
+
+	values, err := GetValues("ssid", "Sheet1!A1:B2", srv)
+	if err != nil {
+		t.Fatalf("GetValues: %v", err)
+	}
+	if len(values) != 2 || values[1][0] != "a" || values[1][1] != "12" {
+		t.Errorf("values = %v", values)
+	}
+
+	call := api.Calls()[0]
+	if call.Method != http.MethodGet || call.Path != "/v4/spreadsheets/ssid/values/Sheet1!A1:B2" {
+		t.Errorf("call = %s %s, want a values get", call.Method, call.Path)
+	}
+}
+
+func TestGetValuesEmpty(t *testing.T) {
+	srv, _ := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusOK, &sheets.ValueRange{Range: "Sheet1!A1:B2"}
+	})
+
+	if _, err := GetValues("ssid", "Sheet1!A1:B2", srv); !errors.Is(err, ErrNoData) {
+		t.Errorf("err = %v, want ErrNoData", err)
+	}
+}
+
+func TestGetValuesError(t *testing.T) {
+	srv, _ := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusNotFound, apiError(http.StatusNotFound)
+	})
+
+	if _, err := GetValues("ssid", "Missing!A1", srv); err == nil || errors.Is(err, ErrNoData) {
+		t.Errorf("err = %v, want the API error", err)
+	}
+}
+
+func TestBatchGetValuesEmptyRanges(t *testing.T) {
+	srv, _ := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		// Empty ranges come back without values, and trailing ones may be left off entirely.
+		return http.StatusOK, &sheets.BatchGetValuesResponse{
+			ValueRanges: []*sheets.ValueRange{
+				{Range: "Header!A1:C1", Values: [][]interface{}{{"a", "b", "c"}}},
+				{Range: "Data!A2:C10"},
+			},
+		}
+	})
+
+	ranges := []string{"Header!A1:C1", "Data!A2:C10", "Notes!A1"}
+	values, err := BatchGetValues("ssid", ranges, srv)
+	if err != nil {
+		t.Fatalf("BatchGetValues: %v", err)
+	}
+
+	if len(values) != len(ranges) {
+		t.Fatalf("got %d ranges, want %d", len(values), len(ranges))
+	}
+	if got := values["Header!A1:C1"]; len(got) != 1 || got[0][2] != "c" {
+		t.Errorf("Header!A1:C1 = %v", got)
+	}
+	for _, rng := range ranges[1:] {
+		if got, ok := values[rng]; !ok || got == nil || len(got) != 0 {
+			t.Errorf("%s = %#v, want an empty slice", rng, got)
+		}
+	}
+}
+
+func TestGetValuesRendered(t *testing.T) {
+	for _, option := range []string{RenderFormatted, RenderUnformatted, RenderFormula} {
+		srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+			return http.StatusOK, &sheets.ValueRange{Values: [][]interface{}{{"=SUM(A1:A3)"}}}
+		})
+
+		values, err := GetValuesRendered("ssid", "Sheet1!B1", option, srv)
+		if err != nil {
+			t.Fatalf("GetValuesRendered(%s): %v", option, err)
+		}
+		if values[0][0] != "=SUM(A1:A3)" {
+			t.Errorf("values = %v", values)
+		}
+		if got := api.Calls()[0].Query.Get("valueRenderOption"); got != option {
+			t.Errorf("valueRenderOption = %q, want %q", got, option)
+		}
+	}
+
+	// GetValues leaves the option to the API default.
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusOK, &sheets.ValueRange{Values: [][]interface{}{{"1"}}}
+	})
+	if _, err := GetValues("ssid", "Sheet1!B1", srv); err != nil {
+		t.Fatalf("GetValues: %v", err)
+	}
+	if _, ok := api.Calls()[0].Query["valueRenderOption"]; ok {
+		t.Error("GetValues sent a valueRenderOption")
+	}
+}
+
+func TestGetValuesRenderedInvalidOption(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	if _, err := GetValuesRendered("ssid", "Sheet1!B1", "formula", srv); !errors.Is(err, ErrRenderOption) {
+		t.Errorf("err = %v, want ErrRenderOption", err)
+	}
+	if len(api.Calls()) != 0 {
+		t.Error("an invalid option called the API")
+	}
+}
+
+func TestClearRange(t *testing.T) {
+	srv, api := newFakeService(t, nil)
+
+	if err := ClearRange("ssid", "Report!A2:F", srv); err != nil {
+		t.Fatalf("ClearRange: %v", err)
+	}
+
+	calls := api.Calls()
+	if len(calls) != 1 || calls[0].Method != http.MethodPost || calls[0].Path != "/v4/spreadsheets/ssid/values/Report!A2:F:clear" {
+		t.Errorf("calls = %+v, want a single values clear", calls)
+	}
+}
+
+func TestClearRangeError(t *testing.T) {
+	srv, _ := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		return http.StatusBadRequest, apiError(http.StatusBadRequest)
+	})
+
+	if err := ClearRange("ssid", "Missing!A1", srv); err == nil || !strings.Contains(err.Error(), "Missing!A1") {
+		t.Errorf("err = %v, want an error naming the range", err)
+	}
+}
+
+func TestAppendValues(t *testing.T) {
+	// The table holds A1:C10, so two rows land in A11:C12.
+	srv, api := newFakeService(t, func(call fakeCall) (int, interface{}) {
+		var vr sheets.ValueRange
+		if err := json.Unmarshal(call.Body, &vr); err != nil {
+			t.Fatalf("decoding value range: %v", err)
+		}
+		end := 10 + len(vr.Values)
+		return http.StatusOK, &sheets.AppendValuesResponse{
+			TableRange: "Log!A1:C10",
+			Updates: &sheets.UpdateValuesResponse{
+				UpdatedRange: fmt.Sprintf("Log!A11:C%d", end),
+				UpdatedRows:  int64(len(vr.Values)),
+			},
+		}
+	})
+
+	values := [][]interface{}{{"a", 1, true}, {"b", 2, false}}
+	updated, err := AppendValues("ssid", "Log!A:C", values, srv)
+	if err != nil {
+		t.Fatalf("AppendValues: %v", err)
+	}
+	if updated != "Log!A11:C12" {
+		t.Errorf("updated range = %q, want Log!A11:C12", updated)
+	}
+
+	// The range can be used to find the appended rows.
+	_, gr, err := parseA1(updated)
+	if err != nil {
+		t.Fatalf("parseA1: %v", err)
+	}
+	if rows := gr.EndRowIndex - gr.StartRowIndex; rows != int64(len(values)) || gr.StartRowIndex != 10 {
+		t.Errorf("range = %+v, want rows 10 to 12", gr)
+	}
+
+	call := api.Calls()[0]
+	if !strings.HasSuffix(call.Path, "/values/Log!A:C:append") {
+		t.Errorf("path = %q", call.Path)
+	}
+	if got := call.Query.Get("insertDataOption"); got != "INSERT_ROWS" {
+		t.Errorf("insertDataOption = %q", got)
+	}
+	if got := call.Query.Get("valueInputOption"); got != "USER_ENTERED" {
+		t.Errorf("valueInputOption = %q", got)
+	}
+}
+
+func TestAppendValuesNoUpdates(t *testing.T) {
+	srv, _ := newFakeService(t, nil)
+
+	if _, err := AppendValues("ssid", "Log", [][]interface{}{{"a"}}, srv); !errors.Is(err, ErrNoData) {
+		t.Errorf("err = %v, want ErrNoData", err)
+	}
+}